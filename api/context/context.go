@@ -8,6 +8,7 @@ import (
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/correlation"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -22,10 +23,13 @@ type CustomContext struct {
 // 	cc.Elapsed[app] += time.Since(start).Nanoseconds() / int64(time.Millisecond)
 // }
 
-// MessageIDFromContext get trace id or generate a new
+// MessageIDFromContext get the request id (set by the RequestID middleware) or generate a new
 func MessageIDFromContext(c echo.Context) string {
 
-	if value := c.Param("id"); value != "" {
+	if ids, ok := correlation.FromContext(c.Request().Context()); ok {
+		return ids.RequestID
+	}
+	if value := c.Request().Header.Get(correlation.RequestIDHeader); value != "" {
 		return value
 	}
 	return uuid.NewV4().String()
@@ -35,7 +39,6 @@ func MessageIDFromContext(c echo.Context) string {
 func ContextRequestHTTP(c echo.Context) map[string]interface{} {
 	var (
 		bodyBytes []byte
-		token     *jwt.Token
 		request   = make(map[string]interface{})
 	)
 
@@ -43,15 +46,16 @@ func ContextRequestHTTP(c echo.Context) map[string]interface{} {
 
 	tokenString := req.Header.Get("Authorization")
 	if tokenString != "" {
-		token, _ = jwt.ParseWithClaims(strings.Replace(tokenString, "Bearer ", "", -1), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
+		// The signature is not verified (the claims are only logged). Malformed tokens are not parsed at all
+		token, _ := jwt.ParseWithClaims(strings.Replace(tokenString, "Bearer ", "", -1), jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
 			return []byte(nil), nil
 		})
 
-		claims := token.Claims.(jwt.MapClaims)
-		if len(claims) != 0 {
-			request["client"] = token.Claims
-		} else {
-			request["client"] = tokenString
+		request["client"] = tokenString
+		if token != nil {
+			if claims, ok := token.Claims.(jwt.MapClaims); ok && len(claims) != 0 {
+				request["client"] = claims
+			}
 		}
 	}
 
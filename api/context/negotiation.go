@@ -0,0 +1,35 @@
+package context
+
+import (
+	"encoding/json"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+)
+
+const (
+	// Context keys set by the ContentNegotiation middleware
+	ResponseMediaTypeKey = "response_media_type"
+	ErrorMediaTypeKey    = "error_media_type"
+)
+
+// ResponseMediaType returns the media type negotiated for the response (application/json by default)
+func ResponseMediaType(c echo.Context) string {
+	if mediaType, ok := c.Get(ResponseMediaTypeKey).(string); ok && mediaType != "" {
+		return mediaType
+	}
+	return echo.MIMEApplicationJSON
+}
+
+// WriteResponseMessage writes the error message as application/problem+json when the client accepts it, or as
+// the plain JSON ResponseMessage otherwise
+func WriteResponseMessage(c echo.Context, responseMessage *responses.ResponseMessage) error {
+	if mediaType, _ := c.Get(ErrorMediaTypeKey).(string); mediaType == responses.MIMEApplicationProblemJSON {
+		body, err := json.Marshal(responseMessage.Problem(c.Request().URL.Path))
+		if err != nil {
+			return err
+		}
+		return c.Blob(responseMessage.HttpStatusCode, responses.MIMEApplicationProblemJSON, body)
+	}
+	return c.JSON(responseMessage.HttpStatusCode, responseMessage)
+}
@@ -0,0 +1,83 @@
+package context
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/masking"
+)
+
+const RedactedValue = masking.MaskedValue
+
+// DefaultRedactFields are the field names (and header names) which are never written to the logs. Names are matched
+// ignoring case, underscores and hyphens, and a field is redacted when its name contains any of them
+var DefaultRedactFields = masking.DefaultFieldNames
+
+// RedactRequest redacts the body, headers, query parameters and raw token of a map created by ContextRequestHTTP
+func RedactRequest(request map[string]interface{}, fields []string) map[string]interface{} {
+	return MaskRequest(request, fieldMasker(fields))
+}
+
+// RedactResponse redacts the body of a map created by ContextResponseHTTP
+func RedactResponse(response map[string]interface{}, fields []string) map[string]interface{} {
+	return MaskResponse(response, fieldMasker(fields))
+}
+
+// RedactJSON redacts the values of the sensitive fields of a JSON document. Documents which are not valid JSON are
+// returned unchanged
+func RedactJSON(document string, fields []string) string {
+	return fieldMasker(fields).MaskJSON(document)
+}
+
+// RedactURI redacts the values of the sensitive query parameters
+func RedactURI(uri string, fields []string) string {
+	return fieldMasker(fields).MaskURI(uri)
+}
+
+// MaskRequest masks the body, headers, query parameters and raw token of a map created by ContextRequestHTTP. Unlike
+// RedactRequest, the masker also applies its header rules and value patterns (e.g. card numbers)
+func MaskRequest(request map[string]interface{}, masker *masking.Masker) map[string]interface{} {
+	if body, ok := request["body"].(string); ok {
+		request["body"] = masker.MaskJSON(body)
+	}
+	if header, ok := request["header"].(string); ok {
+		request["header"] = maskHeaderJSON(header, masker)
+	}
+	if uri, ok := request["uri"].(string); ok {
+		request["uri"] = masker.MaskURI(uri)
+	}
+	if queryParams, ok := request["query_params"].(url.Values); ok {
+		request["query_params"] = masker.MaskQuery(queryParams)
+	}
+	if _, ok := request["client"].(string); ok {
+		// Raw token which could not be parsed
+		request["client"] = RedactedValue
+	}
+	return request
+}
+
+// MaskResponse masks the body of a map created by ContextResponseHTTP
+func MaskResponse(response map[string]interface{}, masker *masking.Masker) map[string]interface{} {
+	if body, ok := response["body"].(string); ok {
+		response["body"] = masker.MaskJSON(body)
+	}
+	return response
+}
+
+// fieldMasker masks only the fields named after the field names
+func fieldMasker(fields []string) *masking.Masker {
+	return masking.New(masking.WithFieldNames(fields...))
+}
+
+func maskHeaderJSON(document string, masker *masking.Masker) string {
+	header := http.Header{}
+	if err := json.Unmarshal([]byte(document), &header); err != nil {
+		return masker.MaskJSON(document)
+	}
+	masked, err := json.Marshal(masker.MaskHeaders(header))
+	if err != nil {
+		return masker.MaskJSON(document)
+	}
+	return string(masked)
+}
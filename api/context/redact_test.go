@@ -0,0 +1,65 @@
+package context
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/masking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactJSON(t *testing.T) {
+	document := `{"name":"John","password":"123456","account":{"account_number":"0001-2","bank":"001"},"cards":[{"cardNumber":"4111111111111111"}]}`
+
+	redacted := RedactJSON(document, DefaultRedactFields)
+
+	assert.JSONEq(t, `{"name":"John","password":"[REDACTED]","account":{"account_number":"[REDACTED]","bank":"001"},"cards":[{"cardNumber":"[REDACTED]"}]}`, redacted)
+	assert.Equal(t, "not json", RedactJSON("not json", DefaultRedactFields))
+}
+
+func TestRedactRequest(t *testing.T) {
+	request := map[string]interface{}{
+		"body":         `{"login":"john","password":"123456"}`,
+		"header":       `{"Authorization":["Bearer 123"],"Content-Type":["application/json"]}`,
+		"client":       "Bearer 123",
+		"uri":          "/v1/statements?access_token=123&page=2",
+		"query_params": url.Values{"access_token": {"123"}, "page": {"2"}},
+	}
+
+	RedactRequest(request, DefaultRedactFields)
+
+	assert.JSONEq(t, `{"login":"john","password":"[REDACTED]"}`, request["body"].(string))
+	assert.JSONEq(t, `{"Authorization":["[REDACTED]"],"Content-Type":["application/json"]}`, request["header"].(string))
+	assert.Equal(t, RedactedValue, request["client"])
+	assert.Equal(t, "/v1/statements?access_token=%5BREDACTED%5D&page=2", request["uri"])
+	assert.Equal(t, url.Values{"access_token": {RedactedValue}, "page": {"2"}}, request["query_params"])
+}
+
+func TestMaskRequest(t *testing.T) {
+	request := map[string]interface{}{
+		"body":         `{"login":"john@example.com","password":"123456","card":{"number":"4111 1111 1111 1111"}}`,
+		"header":       `{"Authorization":["Bearer 123"],"Content-Type":["application/json"]}`,
+		"client":       "Bearer 123",
+		"uri":          "/v1/statements?access_token=123&page=2",
+		"query_params": url.Values{"access_token": {"123"}, "page": {"2"}},
+	}
+
+	MaskRequest(request, masking.Default())
+
+	assert.JSONEq(t, `{"login":"j***@example.com","password":"[REDACTED]","card":{"number":"**** **** **** 1111"}}`, request["body"].(string))
+	assert.JSONEq(t, `{"Authorization":["[REDACTED]"],"Content-Type":["application/json"]}`, request["header"].(string))
+	assert.Equal(t, RedactedValue, request["client"])
+	assert.Equal(t, "/v1/statements?access_token=%5BREDACTED%5D&page=2", request["uri"])
+	assert.Equal(t, url.Values{"access_token": {RedactedValue}, "page": {"2"}}, request["query_params"])
+}
+
+func TestMaskResponse(t *testing.T) {
+	response := map[string]interface{}{
+		"body":        `{"id":"1","token":"abc"}`,
+		"status_code": 200,
+	}
+
+	MaskResponse(response, masking.Default())
+
+	assert.JSONEq(t, `{"id":"1","token":"[REDACTED]"}`, response["body"].(string))
+}
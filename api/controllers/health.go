@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/health"
+)
+
+// NewHealthRoutes exposes the liveness (/health/live) and readiness (/health/ready) probes, to be mounted on a group
+// without version. The readiness answers 503 when any dependency is down
+func NewHealthRoutes(h *health.Health) []Route {
+	return []Route{
+		{
+			URI:    "/health/live",
+			Method: http.MethodGet,
+			Function: func(c echo.Context) error {
+				return c.JSON(http.StatusOK, h.Live())
+			},
+		},
+		{
+			URI:    "/health/ready",
+			Method: http.MethodGet,
+			Function: func(c echo.Context) error {
+				report := h.Ready(c.Request().Context())
+				if report.Status != health.Up {
+					return c.JSON(http.StatusServiceUnavailable, report)
+				}
+				return c.JSON(http.StatusOK, report)
+			},
+		},
+	}
+}
@@ -1,10 +1,46 @@
 package controllers
 
-import "github.com/labstack/echo/v4"
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/middlewares"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/auth"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/ratelimit"
+)
 
 type Route struct {
 	URI                    string
 	Method                 string
 	Function               func(c echo.Context) error
 	RequiresAuthentication bool
+	// Accepted authentication schemes. Only bearer tokens are accepted when empty
+	AuthenticationSchemes []auth.Scheme
+	// Scopes required from service identities (client credentials tokens and API keys)
+	RequiredScopes []string
+	// Requires users to have completed the multi-factor authentication
+	RequiresMFA bool
+	// Reads the bearer tokens of the route (e.g. from the session cookie). The Authorization header when nil
+	TokenExtractor auth.ITokenExtractor
+	// Rate limit applied to the route (optional)
+	RateLimit *ratelimit.Policy
+	// Replays the stored response to the retries sent with the same Idempotency-Key header (POST and PATCH). Requires
+	// the authentication, since the responses are stored per caller
+	Idempotent bool
+	// Media types accepted on the request body and produced by the route. Only JSON when empty
+	Consumes []string
+	Produces []string
+	// Time to answer the request (optional). settings.Config.RequestTimeout is used when zero
+	Timeout time.Duration
+	// Version served by the route (optional). Overrides the version of the group
+	Version string
+	// Deprecation and sunset of the route (optional), announced on the response headers
+	Deprecation *middlewares.DeprecationConfig
+
+	// Documentation (OpenAPI). Request and Response are values of the body types, e.g. CreateUserRequest{}
+	Summary        string
+	Tags           []string
+	Request        interface{}
+	Response       interface{}
+	ResponseStatus int // 200 when zero
 }
@@ -0,0 +1,249 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/middlewares"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/auth"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/idempotency"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/ratelimit"
+)
+
+var (
+	ErrDuplicateRoute             = errors.New("duplicate route")
+	ErrRateLimitStoreMissing      = errors.New("rate limit store is required by the routes with a rate limit")
+	ErrDefaultVersionMissing      = errors.New("default version is not served by the route")
+	ErrIdempotencyStoreMissing    = errors.New("idempotency store is required by the idempotent routes")
+	ErrIdempotencyUnauthenticated = errors.New("idempotent routes require the authentication")
+)
+
+// RouteGroup is a set of routes sharing a base path, a version and middlewares. The routes are mounted on
+// /<version><base path><route URI>
+type RouteGroup struct {
+	BasePath    string
+	Version     string
+	Middlewares []echo.MiddlewareFunc
+	Routes      []Route
+}
+
+// RoutePath returns the path the route is mounted on
+func (group RouteGroup) RoutePath(route Route) string {
+	return joinPath(group.RouteVersion(route), group.BasePath, route.URI)
+}
+
+// RouteVersion returns the version of the route, which overrides the version of the group
+func (group RouteGroup) RouteVersion(route Route) string {
+	if route.Version != "" {
+		return route.Version
+	}
+	return group.Version
+}
+
+// RegisteredRoute describes a route mounted by the router (diagnostics)
+type RegisteredRoute struct {
+	Method                 string
+	Path                   string
+	Version                string
+	RequiresAuthentication bool
+}
+
+type Router struct {
+	// Store used to validate API keys, for the routes accepting the APIKeyScheme
+	APIKeyStore auth.IAPIKeyStore
+	// Store of the rate limit counters. Required when any route has a rate limit
+	RateLimitStore ratelimit.IStore
+	// Store of the idempotent responses. Required when any route is idempotent
+	IdempotencyStore idempotency.IStore
+	// Resolves the version of the unversioned paths from the request headers (optional). The versioned routes are
+	// only mounted with the version prefix when nil
+	Versioning *VersioningConfig
+
+	groups []RouteGroup
+	routes []RegisteredRoute
+}
+
+func NewRouter(groups ...RouteGroup) *Router {
+	return &Router{
+		groups: groups,
+	}
+}
+
+func (router *Router) AddGroup(group RouteGroup) *Router {
+	router.groups = append(router.groups, group)
+	return router
+}
+
+// Register mounts the routes of all the groups on echo. Nothing is mounted when any route is invalid or
+// duplicated (by method and path, including the routes already registered on echo)
+func (router *Router) Register(e *echo.Echo) error {
+	registered := make(map[string]bool)
+	for _, route := range e.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	var routes []RegisteredRoute
+	// Versions of the unversioned paths
+	routeVersions := make(map[string][]string)
+	var unversionedKeys []string
+	for _, group := range router.groups {
+		for _, route := range group.Routes {
+			method := strings.ToUpper(route.Method)
+			routePath := group.RoutePath(route)
+			key := method + " " + routePath
+
+			if registered[key] {
+				return fmt.Errorf("%w: %s", ErrDuplicateRoute, key)
+			}
+			if route.RateLimit != nil {
+				if router.RateLimitStore == nil {
+					return fmt.Errorf("%w: %s", ErrRateLimitStoreMissing, key)
+				}
+				if err := route.RateLimit.Validate(); err != nil {
+					return fmt.Errorf("%s: %w", key, err)
+				}
+			}
+			if route.Idempotent {
+				if router.IdempotencyStore == nil {
+					return fmt.Errorf("%w: %s", ErrIdempotencyStoreMissing, key)
+				}
+				if !route.RequiresAuthentication {
+					return fmt.Errorf("%w: %s", ErrIdempotencyUnauthenticated, key)
+				}
+			}
+			if version := group.RouteVersion(route); router.Versioning != nil && version != "" {
+				unversionedKey := method + " " + joinPath(group.BasePath, route.URI)
+				if _, ok := routeVersions[unversionedKey]; !ok {
+					unversionedKeys = append(unversionedKeys, unversionedKey)
+				}
+				routeVersions[unversionedKey] = append(routeVersions[unversionedKey], normalizeVersion(version))
+			}
+			registered[key] = true
+			routes = append(routes, RegisteredRoute{
+				Method:                 method,
+				Path:                   routePath,
+				Version:                group.RouteVersion(route),
+				RequiresAuthentication: route.RequiresAuthentication,
+			})
+		}
+	}
+
+	if router.Versioning != nil && router.Versioning.DefaultVersion != "" {
+		for _, key := range unversionedKeys {
+			if !registered[key] && !router.Versioning.servesDefaultVersion(routeVersions[key]) {
+				return fmt.Errorf("%w: %s %s", ErrDefaultVersionMissing, key, normalizeVersion(router.Versioning.DefaultVersion))
+			}
+		}
+	}
+
+	// Unversioned paths dispatching to the versions of the route
+	versioned := make(map[string][]versionedHandler)
+	var versionedKeys []string
+
+	index := 0
+	for _, group := range router.groups {
+		for _, route := range group.Routes {
+			middlewareChain := append(append([]echo.MiddlewareFunc{}, group.Middlewares...), router.routeMiddlewares(route)...)
+			e.Add(routes[index].Method, routes[index].Path, route.Function, middlewareChain...)
+
+			if version := group.RouteVersion(route); router.Versioning != nil && version != "" {
+				key := routes[index].Method + " " + joinPath(group.BasePath, route.URI)
+				if _, ok := versioned[key]; !ok {
+					versionedKeys = append(versionedKeys, key)
+				}
+				versioned[key] = append(versioned[key], versionedHandler{
+					version: normalizeVersion(version),
+					handler: applyMiddlewares(route.Function, middlewareChain),
+				})
+			}
+			index++
+		}
+	}
+
+	for _, key := range versionedKeys {
+		// Paths explicitly registered without version win
+		if registered[key] {
+			continue
+		}
+		method, unversionedPath, _ := strings.Cut(key, " ")
+		e.Add(method, unversionedPath, router.Versioning.versionDispatcher(versioned[key]))
+	}
+
+	router.routes = append(router.routes, routes...)
+	return nil
+}
+
+func applyMiddlewares(handler echo.HandlerFunc, middlewareChain []echo.MiddlewareFunc) echo.HandlerFunc {
+	for i := len(middlewareChain) - 1; i >= 0; i-- {
+		handler = middlewareChain[i](handler)
+	}
+	return handler
+}
+
+// Routes lists the routes mounted by the router, sorted by path and method
+func (router *Router) Routes() []RegisteredRoute {
+	routes := append([]RegisteredRoute{}, router.routes...)
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+// routeMiddlewares builds the chain described by the route: timeout, content negotiation, deprecation, authentication,
+// rate limit and idempotency
+func (router *Router) routeMiddlewares(route Route) []echo.MiddlewareFunc {
+	middlewareChain := []echo.MiddlewareFunc{
+		middlewares.TimeoutWithConfig(middlewares.TimeoutConfig{Timeout: route.Timeout}),
+		middlewares.ContentNegotiation(middlewares.ContentNegotiationConfig{
+			Consumes: route.Consumes,
+			Produces: route.Produces,
+		}),
+	}
+
+	if route.Deprecation != nil {
+		middlewareChain = append(middlewareChain, middlewares.Deprecation(*route.Deprecation))
+	}
+
+	if route.RequiresAuthentication {
+		middlewareChain = append(middlewareChain, middlewares.AuthenticateWithConfig(middlewares.AuthenticationConfig{
+			Schemes:        route.AuthenticationSchemes,
+			RequiredScopes: route.RequiredScopes,
+			RequireMFA:     route.RequiresMFA,
+			APIKeyStore:    router.APIKeyStore,
+			TokenExtractor: route.TokenExtractor,
+		}))
+	}
+
+	// After the authentication, so the policies keyed by user or tenant can use the identity
+	if route.RateLimit != nil {
+		middlewareChain = append(middlewareChain, middlewares.RateLimit(middlewares.RateLimitConfig{
+			Policy: *route.RateLimit,
+			Store:  router.RateLimitStore,
+		}))
+	}
+
+	// After the authentication, so the responses are stored per caller, and after the rate limit, so the rejected
+	// requests are not stored
+	if route.Idempotent {
+		middlewareChain = append(middlewareChain, middlewares.Idempotency(middlewares.IdempotencyConfig{
+			Store: router.IdempotencyStore,
+		}))
+	}
+	return middlewareChain
+}
+
+func joinPath(elements ...string) string {
+	joined := path.Join(append([]string{"/"}, elements...)...)
+	// path.Join removes the trailing slash, which echo treats as a different route
+	if last := elements[len(elements)-1]; strings.HasSuffix(last, "/") && joined != "/" {
+		joined += "/"
+	}
+	return joined
+}
@@ -0,0 +1,281 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/middlewares"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/auth"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/health"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/idempotency"
+	"github.com/marcelofelixsalgado/financial-commons/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouterRegister(t *testing.T) {
+	// The authentication failure is logged
+	settings.Config.LogLevel = "error"
+	settings.Config.LogAppFile = filepath.Join(t.TempDir(), "app.log")
+
+	groupMiddlewareCalls := 0
+	handler := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+
+	router := NewRouter(RouteGroup{
+		BasePath: "/users",
+		Version:  "v1",
+		Middlewares: []echo.MiddlewareFunc{
+			func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					groupMiddlewareCalls++
+					return next(c)
+				}
+			},
+		},
+		Routes: []Route{
+			{URI: "", Method: http.MethodGet, Function: handler},
+			{URI: "/:id", Method: http.MethodGet, Function: handler, RequiresAuthentication: true},
+		},
+	})
+
+	e := echo.New()
+	assert.Nil(t, router.Register(e))
+	assert.Equal(t, []RegisteredRoute{
+		{Method: http.MethodGet, Path: "/v1/users", Version: "v1"},
+		{Method: http.MethodGet, Path: "/v1/users/:id", Version: "v1", RequiresAuthentication: true},
+	}, router.Routes())
+
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/users", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, 1, groupMiddlewareCalls)
+
+	// Authentication is applied per route
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/users/1", nil))
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestRouterIdempotency(t *testing.T) {
+	settings.Config.LogLevel = "error"
+	settings.Config.LogAppFile = filepath.Join(t.TempDir(), "app.log")
+	settings.Config.SecretKey = []byte("secret")
+
+	handler := func(c echo.Context) error {
+		identity, _ := auth.IdentityFromContext(c.Request().Context())
+		return c.JSON(http.StatusCreated, map[string]string{"owner": identity.Subject})
+	}
+	route := Route{URI: "", Method: http.MethodPost, Function: handler, RequiresAuthentication: true, Idempotent: true}
+
+	err := NewRouter(RouteGroup{BasePath: "/transactions", Version: "v1", Routes: []Route{route}}).Register(echo.New())
+	assert.True(t, errors.Is(err, ErrIdempotencyStoreMissing))
+
+	router := NewRouter(RouteGroup{BasePath: "/transactions", Version: "v1", Routes: []Route{
+		{URI: "", Method: http.MethodPost, Function: handler, Idempotent: true},
+	}})
+	router.IdempotencyStore = idempotency.NewMemoryStore()
+	assert.True(t, errors.Is(router.Register(echo.New()), ErrIdempotencyUnauthenticated))
+
+	router = NewRouter(RouteGroup{BasePath: "/transactions", Version: "v1", Routes: []Route{route}})
+	router.IdempotencyStore = idempotency.NewMemoryStore()
+	e := echo.New()
+	assert.Nil(t, router.Register(e))
+
+	post := func(token string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/v1/transactions", strings.NewReader(`{"amount":10}`))
+		request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		request.Header.Set(idempotency.Header, "key-1")
+		if token != "" {
+			request.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// The authentication failure is not stored for the key
+	assert.Equal(t, http.StatusForbidden, post("").Code)
+
+	tokenA, _ := auth.CreateToken("user-a", "tenant-1")
+	tokenB, _ := auth.CreateToken("user-b", "tenant-1")
+	first := post(tokenA)
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Contains(t, first.Body.String(), "user-a")
+
+	// The same key, path and body sent by another user is not answered with the stored response
+	other := post(tokenB)
+	assert.Equal(t, http.StatusCreated, other.Code)
+	assert.Contains(t, other.Body.String(), "user-b")
+
+	retry := post(tokenA)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get(idempotency.ReplayedHeader))
+}
+
+func TestRouterRegisterDuplicateRoute(t *testing.T) {
+	handler := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+
+	router := NewRouter().
+		AddGroup(RouteGroup{BasePath: "/users", Version: "v1", Routes: []Route{{URI: "/:id", Method: http.MethodGet, Function: handler}}}).
+		AddGroup(RouteGroup{BasePath: "/", Version: "v1", Routes: []Route{{URI: "/users/:id", Method: "get", Function: handler}}})
+
+	e := echo.New()
+	err := router.Register(e)
+	assert.True(t, errors.Is(err, ErrDuplicateRoute))
+	assert.Empty(t, router.Routes())
+	assert.Empty(t, e.Routes())
+}
+
+func TestRouterVersioning(t *testing.T) {
+	versionHandler := func(version string) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			resolved, _ := c.Get(VersionKey).(string)
+			return c.String(http.StatusOK, version+":"+resolved)
+		}
+	}
+	sunset := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	router := NewRouter(
+		RouteGroup{BasePath: "/users", Version: "v1", Routes: []Route{
+			{URI: "", Method: http.MethodGet, Function: versionHandler("v1"), Deprecation: &middlewares.DeprecationConfig{
+				Date:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+				Sunset: sunset,
+			}},
+		}},
+		RouteGroup{BasePath: "/users", Version: "v2", Routes: []Route{
+			{URI: "", Method: http.MethodGet, Function: versionHandler("v2")},
+			{URI: "", Method: http.MethodGet, Function: versionHandler("v4"), Version: "v4"},
+		}},
+	)
+	router.Versioning = &VersioningConfig{Header: "X-API-Version", AcceptParameter: "version", Fallback: FallbackPrevious}
+
+	e := echo.New()
+	assert.Nil(t, router.Register(e))
+
+	tests := []struct {
+		uri    string
+		header string
+		accept string
+		status int
+		body   string
+	}{
+		{"/v1/users", "v2", "", http.StatusOK, "v1:"},
+		{"/users", "", "", http.StatusOK, "v4:v4"},
+		{"/users", "1", "", http.StatusOK, "v1:v1"},
+		{"/users", "", "application/json; version=2", http.StatusOK, "v2:v2"},
+		{"/users", "3", "", http.StatusOK, "v2:v2"},
+		{"/users", "0", "", http.StatusNotAcceptable, ""},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, test.uri, nil)
+		request.Header.Set("X-API-Version", test.header)
+		request.Header.Set(echo.HeaderAccept, test.accept)
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+
+		assert.Equal(t, test.status, recorder.Code, test.uri+" "+test.header+test.accept)
+		if test.body != "" {
+			assert.Equal(t, test.body, recorder.Body.String(), test.uri+" "+test.header+test.accept)
+		}
+	}
+
+	// The deprecation headers are sent by the deprecated version only
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/users", nil))
+	assert.Equal(t, "@1767225600", recorder.Header().Get(middlewares.DeprecationHeader))
+	assert.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", recorder.Header().Get(middlewares.SunsetHeader))
+
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v2/users", nil))
+	assert.Empty(t, recorder.Header().Get(middlewares.DeprecationHeader))
+}
+
+func TestRouterVersioningDefaultVersion(t *testing.T) {
+	handler := func(c echo.Context) error {
+		resolved, _ := c.Get(VersionKey).(string)
+		return c.String(http.StatusOK, resolved)
+	}
+	newRouter := func(defaultVersion string) *Router {
+		router := NewRouter(
+			RouteGroup{BasePath: "/users", Version: "v1", Routes: []Route{{URI: "", Method: http.MethodGet, Function: handler}}},
+			RouteGroup{BasePath: "/users", Version: "v3", Routes: []Route{{URI: "", Method: http.MethodGet, Function: handler}}},
+		)
+		router.Versioning = &VersioningConfig{AcceptParameter: "version", DefaultVersion: defaultVersion}
+		return router
+	}
+
+	e := echo.New()
+	err := newRouter("2").Register(e)
+	assert.True(t, errors.Is(err, ErrDefaultVersionMissing))
+	assert.Empty(t, e.Routes())
+
+	e = echo.New()
+	assert.Nil(t, newRouter("1").Register(e))
+
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/users", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "v1", recorder.Body.String())
+
+	request := httptest.NewRequest(http.MethodGet, "/users", nil)
+	request.Header.Set(echo.HeaderAccept, "application/json; version=2")
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusNotAcceptable, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"field":"Accept"`)
+
+	// The default version is reported on the Accept header when there is no version header
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/users", nil), httptest.NewRecorder())
+	version, _, field := VersioningConfig{AcceptParameter: "version", DefaultVersion: "2"}.requestedVersion(c)
+	assert.Equal(t, "v2", version)
+	assert.Equal(t, echo.HeaderAccept, field)
+}
+
+func TestHealthRoutes(t *testing.T) {
+	// The check failure is logged
+	settings.Config.LogLevel = "error"
+	settings.Config.LogAppFile = filepath.Join(t.TempDir(), "app.log")
+
+	down := false
+	h := health.New(health.NewChecker("database", func(ctx context.Context) error {
+		if down {
+			return errors.New("connection refused")
+		}
+		return nil
+	}))
+	h.CacheTTL = 0
+
+	e := echo.New()
+	assert.Nil(t, NewRouter(RouteGroup{Routes: NewHealthRoutes(h)}).Register(e))
+
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	down = true
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	report := health.Report{}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, health.Down, report.Status)
+	assert.Equal(t, "database", report.Checks[0].Name)
+	assert.Equal(t, health.Down, report.Checks[0].Status)
+	assert.NotContains(t, recorder.Body.String(), "connection refused")
+}
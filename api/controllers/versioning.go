@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/context"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+)
+
+// Context key of the API version resolved for the request
+const VersionKey = "api_version"
+
+// FallbackPolicy defines the version answering a request for a version not served by the route
+type FallbackPolicy string
+
+const (
+	// FallbackNone rejects the request with UNSUPPORTED_VERSION
+	FallbackNone FallbackPolicy = "none"
+	// FallbackLatest answers with the latest version
+	FallbackLatest FallbackPolicy = "latest"
+	// FallbackPrevious answers with the latest version older than the requested one, or rejects the request when
+	// there is none
+	FallbackPrevious FallbackPolicy = "previous"
+)
+
+// VersioningConfig mounts the versioned routes also without the version prefix (/<base path><route URI>), resolving
+// the version from the request headers. The URL prefix keeps working and always wins
+type VersioningConfig struct {
+	// Header carrying the version, e.g. X-API-Version: 2 (optional)
+	Header string
+	// Parameter of the Accept media type carrying the version, e.g. Accept: application/json; version=2 (optional)
+	AcceptParameter string
+	// Version used when the request does not ask for any. The latest version when empty. Every versioned route must
+	// serve it, or fall back to a version it serves
+	DefaultVersion string
+	// Policy for the versions not served by the route. FallbackNone when empty
+	Fallback FallbackPolicy
+}
+
+type versionedHandler struct {
+	version string
+	handler echo.HandlerFunc
+}
+
+// versionDispatcher answers an unversioned path with the handler of the resolved version
+func (config VersioningConfig) versionDispatcher(handlers []versionedHandler) echo.HandlerFunc {
+	sortVersionedHandlers(handlers)
+
+	return func(c echo.Context) error {
+		if config.Header != "" {
+			c.Response().Header().Add(echo.HeaderVary, config.Header)
+		}
+		if config.AcceptParameter != "" {
+			c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+		}
+
+		requested, location, field := config.requestedVersion(c)
+		handler, ok := config.resolve(handlers, requested)
+		if !ok {
+			responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByIssue(faults.UnsupportedVersion, location, field, requested)
+			return context.WriteResponseMessage(c, responseMessage)
+		}
+
+		c.Set(VersionKey, handler.version)
+		return handler.handler(c)
+	}
+}
+
+// requestedVersion returns the version asked by the request (header first, then Accept) and where it was found
+func (config VersioningConfig) requestedVersion(c echo.Context) (string, responses.Location, string) {
+	if config.Header != "" {
+		if version := c.Request().Header.Get(config.Header); version != "" {
+			return normalizeVersion(version), responses.Header, config.Header
+		}
+	}
+	if config.AcceptParameter != "" {
+		for _, value := range strings.Split(c.Request().Header.Get(echo.HeaderAccept), ",") {
+			_, params, err := mime.ParseMediaType(strings.TrimSpace(value))
+			if err != nil {
+				continue
+			}
+			if version := params[config.AcceptParameter]; version != "" {
+				return normalizeVersion(version), responses.Header, echo.HeaderAccept
+			}
+		}
+	}
+	if config.Header != "" {
+		return normalizeVersion(config.DefaultVersion), responses.Header, config.Header
+	}
+	return normalizeVersion(config.DefaultVersion), responses.Header, echo.HeaderAccept
+}
+
+// servesDefaultVersion reports whether the default version resolves to one of the versions of a route
+func (config VersioningConfig) servesDefaultVersion(versions []string) bool {
+	handlers := make([]versionedHandler, 0, len(versions))
+	for _, version := range versions {
+		handlers = append(handlers, versionedHandler{version: version})
+	}
+	sortVersionedHandlers(handlers)
+	_, ok := config.resolve(handlers, normalizeVersion(config.DefaultVersion))
+	return ok
+}
+
+// resolve picks the handler of the version applying the fallback policy. Handlers are sorted by version
+func (config VersioningConfig) resolve(handlers []versionedHandler, version string) (versionedHandler, bool) {
+	latest := handlers[len(handlers)-1]
+	if version == "" {
+		return latest, true
+	}
+	for _, handler := range handlers {
+		if handler.version == version {
+			return handler, true
+		}
+	}
+
+	switch config.Fallback {
+	case FallbackLatest:
+		return latest, true
+	case FallbackPrevious:
+		for i := len(handlers) - 1; i >= 0; i-- {
+			if compareVersions(handlers[i].version, version) < 0 {
+				return handlers[i], true
+			}
+		}
+	}
+	return versionedHandler{}, false
+}
+
+func sortVersionedHandlers(handlers []versionedHandler) {
+	sort.Slice(handlers, func(i, j int) bool {
+		return compareVersions(handlers[i].version, handlers[j].version) < 0
+	})
+}
+
+// normalizeVersion accepts the versions with or without the "v" prefix: 2 and v2 are the same version
+func normalizeVersion(version string) string {
+	version = strings.ToLower(strings.TrimSpace(version))
+	if version == "" || strings.HasPrefix(version, "v") {
+		return version
+	}
+	return "v" + version
+}
+
+// compareVersions compares the versions numerically (v2 < v10), falling back to the lexical order
+func compareVersions(a string, b string) int {
+	numberA, errA := strconv.Atoi(strings.TrimPrefix(normalizeVersion(a), "v"))
+	numberB, errB := strconv.Atoi(strings.TrimPrefix(normalizeVersion(b), "v"))
+	if errA == nil && errB == nil {
+		return numberA - numberB
+	}
+	return strings.Compare(normalizeVersion(a), normalizeVersion(b))
+}
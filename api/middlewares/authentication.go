@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"github.com/marcelofelixsalgado/financial-commons/api/context"
 	"github.com/marcelofelixsalgado/financial-commons/api/responses"
 	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
 	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/logger"
@@ -9,13 +10,50 @@ import (
 	"github.com/marcelofelixsalgado/financial-commons/pkg/auth"
 )
 
+type AuthenticationConfig struct {
+	// Accepted authentication schemes. Only bearer tokens are accepted when empty
+	Schemes []auth.Scheme
+	// Scopes required from service identities. User tokens are not scoped
+	RequiredScopes []string
+	// Requires users to have completed the multi-factor authentication
+	RequireMFA bool
+	// Store used to validate API keys. Required when APIKeyScheme is accepted
+	APIKeyStore auth.IAPIKeyStore
+	// Reads the bearer tokens (e.g. from the session cookie). The Authorization header when nil
+	TokenExtractor auth.ITokenExtractor
+}
+
 func Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		if err := auth.ValidateToken(c.Request()); err != nil {
-			logger.GetLogger().Infof("Token validation error: %v", err)
-			responseMessage := responses.NewResponseMessage().AddMessageByErrorCode(faults.NotAuthorized)
-			return c.JSON(responseMessage.HttpStatusCode, responseMessage)
+	return AuthenticateWithConfig(AuthenticationConfig{})(next)
+}
+
+// AuthenticateWithConfig authenticates the request using any of the configured schemes and stores
+// the caller identity in the request context
+func AuthenticateWithConfig(config AuthenticationConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			identity, err := auth.Authenticate(c.Request(), config.Schemes, config.APIKeyStore, config.TokenExtractor)
+			if err != nil {
+				logger.GetLoggerWithContext(c.Request().Context()).Infof("Token validation error: %v", err)
+				responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByErrorCode(faults.NotAuthorized)
+				return context.WriteResponseMessage(c, responseMessage)
+			}
+
+			if identity.Type == auth.ServiceIdentity && !identity.HasScopes(config.RequiredScopes...) {
+				logger.GetLoggerWithContext(c.Request().Context()).Infof("Client [%s] does not have the required scopes: %v", identity.Subject, config.RequiredScopes)
+				responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByIssue(faults.RequiredScopeMissing, "", "", "")
+				return context.WriteResponseMessage(c, responseMessage)
+			}
+
+			if identity.Type == auth.UserIdentity && config.RequireMFA && !identity.MFA {
+				logger.GetLoggerWithContext(c.Request().Context()).Infof("User [%s] did not complete the multi-factor authentication", identity.Subject)
+				responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByIssue(faults.MFARequired, "", "", "")
+				return context.WriteResponseMessage(c, responseMessage)
+			}
+
+			c.Set("identity", identity)
+			c.SetRequest(c.Request().WithContext(auth.NewContext(c.Request().Context(), identity)))
+			return next(c)
 		}
-		return next(c)
 	}
 }
@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/context"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/auth"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/logger"
+)
+
+type CSRFConfig struct {
+	// Cookie carrying the access token. Only requests authenticated by this cookie are checked
+	SessionCookieName string
+	// Cookie and header which must carry the same token (double-submit cookie)
+	CSRFCookie auth.CookieConfig
+	CSRFHeader string
+}
+
+func CSRF() echo.MiddlewareFunc {
+	return CSRFWithConfig(CSRFConfig{
+		SessionCookieName: auth.SessionCookieName,
+		CSRFCookie:        auth.NewCSRFCookieConfig(),
+		CSRFHeader:        auth.CSRFHeader,
+	})
+}
+
+// CSRFWithConfig protects the cookie authenticated requests against cross-site request forgery. Safe methods get
+// a CSRF cookie (when missing) and unsafe methods must send its value back on the CSRF header
+func CSRFWithConfig(config CSRFConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			request := c.Request()
+
+			if _, err := request.Cookie(config.SessionCookieName); err != nil {
+				// Not a cookie session (e.g. Authorization header): the browser does not send the credentials by itself
+				return next(c)
+			}
+
+			cookie, err := request.Cookie(config.CSRFCookie.Name)
+
+			if isSafeMethod(request.Method) {
+				if err != nil || cookie.Value == "" {
+					token, err := auth.GenerateCSRFToken()
+					if err != nil {
+						logger.GetLoggerWithContext(c.Request().Context()).Errorf("Error trying to generate the CSRF token: %v", err)
+						responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByErrorCode(faults.InternalServerError)
+						return context.WriteResponseMessage(c, responseMessage)
+					}
+					auth.SetCookie(c.Response(), config.CSRFCookie, token)
+				}
+				return next(c)
+			}
+
+			header := request.Header.Get(config.CSRFHeader)
+			if err != nil || cookie.Value == "" || header == "" ||
+				subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(header)) != 1 {
+				logger.GetLoggerWithContext(c.Request().Context()).Infof("CSRF token validation failed: %s %s", request.Method, request.RequestURI)
+				responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByIssue(faults.InvalidCSRFToken, responses.Header, config.CSRFHeader, "")
+				return context.WriteResponseMessage(c, responseMessage)
+			}
+			return next(c)
+		}
+	}
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
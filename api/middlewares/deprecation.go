@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	DeprecationHeader = "Deprecation"
+	SunsetHeader      = "Sunset"
+)
+
+type DeprecationConfig struct {
+	// When the route was (or will be) deprecated
+	Date time.Time
+	// When the route will stop answering (optional)
+	Sunset time.Time
+	// Documentation of the deprecation, e.g. the migration guide to the next version (optional)
+	Link string
+}
+
+// Deprecation announces the deprecation of the route with the Deprecation (RFC 9745), Sunset (RFC 8594) and Link headers
+func Deprecation(config DeprecationConfig) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			header := c.Response().Header()
+			if !config.Date.IsZero() {
+				header.Set(DeprecationHeader, "@"+strconv.FormatInt(config.Date.Unix(), 10))
+			}
+			if !config.Sunset.IsZero() {
+				header.Set(SunsetHeader, config.Sunset.UTC().Format(http.TimeFormat))
+			}
+			if config.Link != "" {
+				header.Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"`, config.Link))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/context"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/logger"
+)
+
+// HTTPErrorHandler answers the errors returned by the handlers (and raised by echo itself, as unknown routes) with
+// the fault catalog responses instead of echo's default output. Use it as echo.Echo.HTTPErrorHandler
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	responseMessage := errorResponseMessage(err, c)
+	if responseMessage.HttpStatusCode >= http.StatusInternalServerError {
+		logger.GetLoggerWithContext(c.Request().Context()).Errorf("Error handling the request: %v", err)
+	}
+
+	var writeErr error
+	if c.Request().Method == http.MethodHead {
+		writeErr = c.NoContent(responseMessage.HttpStatusCode)
+	} else {
+		writeErr = context.WriteResponseMessage(c, responseMessage)
+	}
+	if writeErr != nil {
+		logger.GetLoggerWithContext(c.Request().Context()).Errorf("Error writing the error response: %v", writeErr)
+	}
+}
+
+func errorResponseMessage(err error, c echo.Context) *responses.ResponseMessage {
+	responseMessage := responses.NewResponseMessageWithContext(c.Request().Context())
+
+	var httpError *echo.HTTPError
+	if !errors.As(err, &httpError) {
+		return responseMessage.AddMessageByErrorCode(faults.InternalServerError)
+	}
+
+	switch httpError.Code {
+	case http.StatusNotFound:
+		return responseMessage.AddMessageByIssue(faults.InvalidURI, "", "", "")
+	case http.StatusMethodNotAllowed:
+		return responseMessage.AddMessageByIssue(faults.MethodNotSupported, "", "", "")
+	case http.StatusUnsupportedMediaType:
+		if c.Request().Header.Get(echo.HeaderContentType) == "" {
+			return responseMessage.AddMessageByIssue(faults.MissingContentType, responses.Header, echo.HeaderContentType, "")
+		}
+		return responseMessage.AddMessageByIssue(faults.InvalidContentType, responses.Header, echo.HeaderContentType, "")
+	}
+
+	referenceResponse, findErr := faults.FindByHttpStatusCode(httpError.Code)
+	if findErr == nil {
+		return responseMessage.AddMessageByErrorCode(referenceResponse.ErrorCode)
+	}
+	// Statuses without a catalog entry (e.g. 413 from the body limit) keep their code, with the generic body of their
+	// class: 400 for the client errors and 500 for the others
+	errorCode := faults.InternalServerError
+	if httpError.Code >= http.StatusBadRequest && httpError.Code < http.StatusInternalServerError {
+		errorCode = faults.InvalidRequestSyntax
+	}
+	responseMessage.AddMessageByErrorCode(errorCode)
+	if http.StatusText(httpError.Code) != "" {
+		responseMessage.HttpStatusCode = httpError.Code
+	}
+	return responseMessage
+}
@@ -0,0 +1,161 @@
+package middlewares
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/context"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/auth"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/logger"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/idempotency"
+)
+
+const maxIdempotencyKeyLength = 255
+
+type IdempotencyConfig struct {
+	Store idempotency.IStore
+	// How long the responses are kept for replay
+	TTL time.Duration
+}
+
+// Idempotency honours the Idempotency-Key header on POST and PATCH requests: the first response (status and body)
+// per key, user and route is stored and replayed for the retries. The same key with a different payload, or while
+// the first request is still being processed, is answered with CONFLICT. Requests without the header are untouched.
+// It must run after the authentication (see Route.Idempotent): the requests without an identity are not deduplicated,
+// since their responses could be replayed to other callers
+func Idempotency(config IdempotencyConfig) echo.MiddlewareFunc {
+	if config.Store == nil {
+		panic("idempotency store is required")
+	}
+	if config.TTL <= 0 {
+		config.TTL = 24 * time.Hour
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			request := c.Request()
+			if request.Method != http.MethodPost && request.Method != http.MethodPatch {
+				return next(c)
+			}
+
+			idempotencyKey := request.Header.Get(idempotency.Header)
+			if idempotencyKey == "" {
+				return next(c)
+			}
+			identity, authenticated := auth.IdentityFromContext(request.Context())
+			if !authenticated || identity.Subject == "" {
+				logger.GetLoggerWithContext(request.Context()).Warnf("Idempotency key [%s] ignored: the request is not authenticated", idempotencyKey)
+				return next(c)
+			}
+			if len(idempotencyKey) > maxIdempotencyKeyLength {
+				responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByIssue(faults.InvalidStringMaxLength, responses.Header, idempotency.Header, idempotencyKey, strconv.Itoa(maxIdempotencyKeyLength))
+				return context.WriteResponseMessage(c, responseMessage)
+			}
+
+			body, err := io.ReadAll(request.Body)
+			if err != nil {
+				return err
+			}
+			request.Body = io.NopCloser(bytes.NewReader(body))
+
+			key := idempotencyScopedKey(c, identity, idempotencyKey)
+			hash := requestHash(request.Method, request.URL.Path, body)
+			record, started, err := config.Store.Start(key, hash, config.TTL)
+			if err != nil {
+				// Fail open: the store being unavailable must not take the service down
+				logger.GetLoggerWithContext(request.Context()).Errorf("Error trying to start the idempotent request [%s]: %v", idempotencyKey, err)
+				return next(c)
+			}
+
+			if !started {
+				return replayIdempotentResponse(c, record, hash, idempotencyKey)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			err = next(c)
+			c.Response().Writer = recorder.ResponseWriter
+
+			// Errors, authorization failures and server failures are not stored, so the client can retry with the same key
+			status := c.Response().Status
+			if err != nil || !c.Response().Committed || status == http.StatusUnauthorized || status == http.StatusForbidden || status >= http.StatusInternalServerError {
+				if releaseErr := config.Store.Release(key); releaseErr != nil {
+					logger.GetLoggerWithContext(request.Context()).Errorf("Error trying to release the idempotent request [%s]: %v", idempotencyKey, releaseErr)
+				}
+				return err
+			}
+
+			contentType := c.Response().Header().Get(echo.HeaderContentType)
+			if err := config.Store.Complete(key, c.Response().Status, contentType, recorder.body.Bytes()); err != nil {
+				logger.GetLoggerWithContext(request.Context()).Errorf("Error trying to store the idempotent response [%s]: %v", idempotencyKey, err)
+			}
+			return nil
+		}
+	}
+}
+
+func replayIdempotentResponse(c echo.Context, record idempotency.Record, requestHash string, idempotencyKey string) error {
+	if record.RequestHash != requestHash {
+		responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByIssue(faults.IdempotencyKeyReused, responses.Header, idempotency.Header, idempotencyKey)
+		return context.WriteResponseMessage(c, responseMessage)
+	}
+	if record.Status != idempotency.Completed {
+		responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByIssue(faults.IdempotentRequestInProgress, responses.Header, idempotency.Header, idempotencyKey)
+		return context.WriteResponseMessage(c, responseMessage)
+	}
+
+	c.Response().Header().Set(idempotency.ReplayedHeader, "true")
+	if len(record.ResponseBody) == 0 {
+		return c.NoContent(record.ResponseStatus)
+	}
+	return c.Blob(record.ResponseStatus, record.ContentType, record.ResponseBody)
+}
+
+// The key is scoped by caller and route, so different callers (or endpoints) never share the stored responses
+func idempotencyScopedKey(c echo.Context, identity auth.Identity, idempotencyKey string) string {
+	subject := string(identity.Type) + ":" + identity.TenantId + ":" + identity.Subject
+	sum := sha256.Sum256([]byte(subject + "\n" + c.Request().Method + " " + c.Path() + "\n" + idempotencyKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func requestHash(method string, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body written by the handler
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (recorder *responseRecorder) Write(b []byte) (int, error) {
+	recorder.body.Write(b)
+	return recorder.ResponseWriter.Write(b)
+}
+
+func (recorder *responseRecorder) Flush() {
+	if flusher, ok := recorder.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (recorder *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := recorder.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, errors.New("response writer does not support hijacking")
+}
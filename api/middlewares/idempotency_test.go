@@ -0,0 +1,163 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/auth"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/idempotency"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	calls := 0
+	var inFlight *httptest.ResponseRecorder
+
+	e := echo.New()
+	e.Use(testIdentity, Idempotency(IdempotencyConfig{Store: idempotency.NewMemoryStore()}))
+	e.POST("/v1/transactions", func(c echo.Context) error {
+		calls++
+		if c.Request().Header.Get("X-Nested") == "" {
+			// A retry arriving while the first request is still being processed
+			inFlight = serveIdempotent(e, "key-1", `{"amount":10}`, "true")
+		}
+		return c.JSON(http.StatusCreated, map[string]int{"id": calls})
+	})
+
+	first := serveIdempotent(e, "key-1", `{"amount":10}`, "")
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusConflict, inFlight.Code)
+	assert.Contains(t, inFlight.Body.String(), string(faults.IdempotentRequestInProgress))
+
+	retry := serveIdempotent(e, "key-1", `{"amount":10}`, "")
+	assert.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get(idempotency.ReplayedHeader))
+	assert.Equal(t, 1, calls)
+
+	conflict := serveIdempotent(e, "key-1", `{"amount":20}`, "")
+	assert.Equal(t, http.StatusConflict, conflict.Code)
+	assert.Contains(t, conflict.Body.String(), string(faults.IdempotencyKeyReused))
+
+	other := serveIdempotent(e, "key-2", `{"amount":20}`, "true")
+	assert.Equal(t, http.StatusCreated, other.Code)
+	assert.Equal(t, 2, calls)
+
+	withoutKey := serveIdempotent(e, "", `{"amount":10}`, "true")
+	assert.Equal(t, http.StatusCreated, withoutKey.Code)
+	assert.Equal(t, 3, calls)
+}
+
+func TestIdempotencyKeyTooLong(t *testing.T) {
+	e := echo.New()
+	e.Use(testIdentity, Idempotency(IdempotencyConfig{Store: idempotency.NewMemoryStore()}))
+	e.POST("/v1/transactions", func(c echo.Context) error {
+		return c.NoContent(http.StatusCreated)
+	})
+
+	recorder := serveIdempotent(e, strings.Repeat("k", maxIdempotencyKeyLength+1), `{}`, "")
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), string(faults.InvalidStringMaxLength))
+}
+
+func TestIdempotencyServerErrorIsNotStored(t *testing.T) {
+	calls := 0
+
+	e := echo.New()
+	e.Use(testIdentity, Idempotency(IdempotencyConfig{Store: idempotency.NewMemoryStore()}))
+	e.POST("/v1/transactions", func(c echo.Context) error {
+		calls++
+		if calls == 1 {
+			return c.NoContent(http.StatusServiceUnavailable)
+		}
+		return c.NoContent(http.StatusCreated)
+	})
+
+	assert.Equal(t, http.StatusServiceUnavailable, serveIdempotent(e, "key-1", `{}`, "").Code)
+	assert.Equal(t, http.StatusCreated, serveIdempotent(e, "key-1", `{}`, "").Code)
+	assert.Equal(t, http.StatusCreated, serveIdempotent(e, "key-1", `{}`, "").Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyScopedByIdentity(t *testing.T) {
+	initTestLogger(t)
+	calls := 0
+
+	e := echo.New()
+	e.Use(testIdentity, Idempotency(IdempotencyConfig{Store: idempotency.NewMemoryStore()}))
+	e.POST("/v1/transactions", func(c echo.Context) error {
+		calls++
+		identity, _ := auth.IdentityFromContext(c.Request().Context())
+		return c.JSON(http.StatusCreated, map[string]string{"owner": identity.Subject})
+	})
+
+	first := serveIdempotentAs(e, "user-1", "key-1", `{"amount":10}`)
+	second := serveIdempotentAs(e, "user-2", "key-1", `{"amount":10}`)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Contains(t, second.Body.String(), "user-2")
+	assert.Empty(t, second.Header().Get(idempotency.ReplayedHeader))
+	assert.NotEqual(t, first.Body.String(), second.Body.String())
+
+	// Without an identity the key is ignored, so nothing is stored or replayed
+	assert.Equal(t, http.StatusCreated, serveIdempotentAs(e, "", "key-1", `{"amount":10}`).Code)
+	assert.Equal(t, http.StatusCreated, serveIdempotentAs(e, "", "key-1", `{"amount":10}`).Code)
+	assert.Equal(t, 4, calls)
+}
+
+func TestIdempotencyAuthorizationFailureIsNotStored(t *testing.T) {
+	calls := 0
+
+	e := echo.New()
+	e.Use(testIdentity, Idempotency(IdempotencyConfig{Store: idempotency.NewMemoryStore()}))
+	e.POST("/v1/transactions", func(c echo.Context) error {
+		calls++
+		if calls == 1 {
+			return c.NoContent(http.StatusForbidden)
+		}
+		return c.NoContent(http.StatusCreated)
+	})
+
+	assert.Equal(t, http.StatusForbidden, serveIdempotent(e, "key-1", `{}`, "").Code)
+	assert.Equal(t, http.StatusCreated, serveIdempotent(e, "key-1", `{}`, "").Code)
+	assert.Equal(t, 2, calls)
+}
+
+// testIdentity authenticates the requests as the user of the X-User header
+func testIdentity(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if subject := c.Request().Header.Get("X-User"); subject != "" {
+			identity := auth.Identity{Type: auth.UserIdentity, Subject: subject}
+			c.SetRequest(c.Request().WithContext(auth.NewContext(c.Request().Context(), identity)))
+		}
+		return next(c)
+	}
+}
+
+func serveIdempotentAs(e *echo.Echo, user string, key string, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/v1/transactions", strings.NewReader(body))
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	request.Header.Set(idempotency.Header, key)
+	request.Header.Set("X-User", user)
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func serveIdempotent(e *echo.Echo, key string, body string, nested string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/v1/transactions", strings.NewReader(body))
+	request.Header.Set("X-User", "user-1")
+	request.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if key != "" {
+		request.Header.Set(idempotency.Header, key)
+	}
+	if nested != "" {
+		request.Header.Set("X-Nested", nested)
+	}
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+	return recorder
+}
@@ -1,9 +1,16 @@
 package middlewares
 
 import (
+	"io"
 	"log"
 	"os"
+	"sync"
 
+	"github.com/marcelofelixsalgado/financial-commons/api/context"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/auth"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/masking"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/correlation"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tenant"
 	"github.com/marcelofelixsalgado/financial-commons/settings"
 
 	"github.com/labstack/echo/v4"
@@ -11,39 +18,153 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+type AccessLogConfig struct {
+	// Destination of the access log. Defaults to settings.Config.LogAccessFile
+	Output io.Writer
+	// Adds the request and response bodies (see context.ContextRequestHTTP and context.ContextResponseHTTP)
+	CaptureBodies bool
+	// Fields redacted from the captured bodies and headers. Defaults to context.DefaultRedactFields
+	RedactFields []string
+	// Masks the URI and the captured bodies and headers. Defaults to a masker redacting the RedactFields, the
+	// masking.DefaultHeaders and the masking.BuiltinPatterns
+	Masker  *masking.Masker
+	Skipper middleware.Skipper
+}
+
+var (
+	accessLogFile      *os.File
+	accessLogFileMutex sync.Mutex
+	routeNames         sync.Map
+)
+
 func Logger() echo.MiddlewareFunc {
+	return LoggerWithConfig(AccessLogConfig{
+		CaptureBodies: settings.Config.LogCaptureBodies,
+	})
+}
 
-	file, err := os.OpenFile(settings.Config.LogAccessFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
-	if err != nil {
-		log.Fatalf("error opening file: %v", err)
+// LoggerWithConfig writes an access log entry (JSON) for each request
+func LoggerWithConfig(config AccessLogConfig) echo.MiddlewareFunc {
+	if config.Output == nil {
+		config.Output = openAccessLogFile()
+	}
+	if config.RedactFields == nil {
+		config.RedactFields = context.DefaultRedactFields
+	}
+	if config.Masker == nil {
+		config.Masker = masking.New(
+			masking.WithFieldNames(config.RedactFields...),
+			masking.WithHeaders(masking.DefaultHeaders...),
+			masking.WithPatterns(masking.BuiltinPatterns()...),
+		)
 	}
 
 	log := logrus.New()
+	log.SetFormatter(&logrus.JSONFormatter{})
+	log.SetOutput(config.Output)
 
 	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
-		LogURI:    true,
-		LogStatus: true,
+		Skipper:          config.Skipper,
+		LogLatency:       true,
+		LogRemoteIP:      true,
+		LogMethod:        true,
+		LogURI:           true,
+		LogRoutePath:     true,
+		LogUserAgent:     true,
+		LogStatus:        true,
+		LogError:         true,
+		LogContentLength: true,
+		LogResponseSize:  true,
+		BeforeNextFunc: func(c echo.Context) {
+			if config.CaptureBodies {
+				c.Set("access_log_request", context.MaskRequest(context.ContextRequestHTTP(c), config.Masker))
+			}
+		},
 		LogValuesFunc: func(c echo.Context, values middleware.RequestLoggerValues) error {
-			log.SetFormatter(&logrus.JSONFormatter{})
-			log.SetOutput(file)
+			fields := logrus.Fields{
+				"method":      values.Method,
+				"URI":         config.Masker.MaskURI(values.URI),
+				"path":        values.RoutePath,
+				"route":       routeName(c),
+				"status":      values.Status,
+				"latency_ms":  float64(values.Latency.Microseconds()) / 1000,
+				"bytes_in":    values.ContentLength,
+				"bytes_out":   values.ResponseSize,
+				"remote_ip":   values.RemoteIP,
+				"user_agent":  values.UserAgent,
+				"environment": settings.Config.Environment,
+				"app.name":    settings.Config.AppName,
+			}
+
+			ctx := c.Request().Context()
+			if ids, ok := correlation.FromContext(ctx); ok {
+				fields["request_id"] = ids.RequestID
+				fields["correlation_id"] = ids.CorrelationID
+			}
+			if identity, ok := auth.IdentityFromContext(ctx); ok {
+				fields["user"] = identity.Subject
+				fields["tenant"] = identity.TenantId
+			}
+			if tenantId, ok := tenant.FromContext(ctx); ok {
+				fields["tenant"] = tenantId
+			}
+
+			if config.CaptureBodies {
+				fields["request"] = c.Get("access_log_request")
+				fields["response"] = context.MaskResponse(context.ContextResponseHTTP(c), config.Masker)
+			}
 
 			if values.Error == nil {
-				log.WithFields(logrus.Fields{
-					"method": c.Request().Method,
-					"URI":    values.URI,
-					"path":   c.Path(),
-					"status": values.Status,
-				}).Info("request")
+				log.WithFields(fields).Info("request")
 			} else {
-				log.WithFields(logrus.Fields{
-					"method": c.Request().Method,
-					"URI":    values.URI,
-					"path":   c.Path(),
-					"status": values.Status,
-					"error":  values.Error,
-				}).Error("request error")
+				fields["error"] = values.Error.Error()
+				log.WithFields(fields).Error("request error")
 			}
 			return nil
 		},
 	})
 }
+
+// CloseAccessLog closes the access log file opened by the Logger middleware
+func CloseAccessLog() error {
+	accessLogFileMutex.Lock()
+	defer accessLogFileMutex.Unlock()
+	if accessLogFile == nil {
+		return nil
+	}
+	err := accessLogFile.Close()
+	accessLogFile = nil
+	return err
+}
+
+// openAccessLogFile opens the access log file once, no matter how many loggers are created
+func openAccessLogFile() *os.File {
+	accessLogFileMutex.Lock()
+	defer accessLogFileMutex.Unlock()
+	if accessLogFile != nil {
+		return accessLogFile
+	}
+
+	file, err := os.OpenFile(settings.Config.LogAccessFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		log.Fatalf("error opening file: %v", err)
+	}
+	accessLogFile = file
+	return accessLogFile
+}
+
+// routeName returns the name of the matched route (echo names the routes after the handler function by default)
+func routeName(c echo.Context) string {
+	key := c.Request().Method + " " + c.Path()
+	if name, ok := routeNames.Load(key); ok {
+		return name.(string)
+	}
+
+	for _, route := range c.Echo().Routes() {
+		if route.Method == c.Request().Method && route.Path == c.Path() {
+			routeNames.Store(key, route.Name)
+			return route.Name
+		}
+	}
+	return ""
+}
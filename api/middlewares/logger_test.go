@@ -0,0 +1,71 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/correlation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerWithConfig(t *testing.T) {
+	output := &bytes.Buffer{}
+
+	e := echo.New()
+	e.Use(RequestID())
+	e.Use(LoggerWithConfig(AccessLogConfig{Output: output, CaptureBodies: true}))
+	e.POST("/v1/users", func(c echo.Context) error {
+		c.Set("response_body", map[string]string{"id": "1", "token": "abc"})
+		return c.JSON(http.StatusCreated, map[string]string{"id": "1", "token": "abc"})
+	}).Name = "createUser"
+
+	request := httptest.NewRequest(http.MethodPost, "/v1/users?page=1", strings.NewReader(`{"login":"john","password":"123456"}`))
+	request.Header.Set(correlation.RequestIDHeader, "request-1")
+	request.Header.Set("User-Agent", "test-agent")
+	e.ServeHTTP(httptest.NewRecorder(), request)
+
+	entry := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(output.Bytes(), &entry))
+
+	assert.Equal(t, "POST", entry["method"])
+	assert.Equal(t, "/v1/users", entry["path"])
+	assert.Equal(t, "createUser", entry["route"])
+	assert.Equal(t, float64(http.StatusCreated), entry["status"])
+	assert.Equal(t, "request-1", entry["request_id"])
+	assert.Equal(t, "test-agent", entry["user_agent"])
+	assert.Contains(t, entry, "latency_ms")
+	assert.Contains(t, entry, "bytes_out")
+
+	requestLog := entry["request"].(map[string]interface{})
+	assert.JSONEq(t, `{"login":"john","password":"[REDACTED]"}`, requestLog["body"].(string))
+	responseLog := entry["response"].(map[string]interface{})
+	assert.JSONEq(t, `{"id":"1","token":"[REDACTED]"}`, responseLog["body"].(string))
+}
+
+func TestLoggerWithConfigMalformedToken(t *testing.T) {
+	for _, authorization := range []string{"Basic x", "Bearer garbage", "Bearer a.b.c"} {
+		output := &bytes.Buffer{}
+
+		e := echo.New()
+		e.Use(LoggerWithConfig(AccessLogConfig{Output: output, CaptureBodies: true}))
+		e.GET("/v1/users", func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+		request := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+		request.Header.Set(echo.HeaderAuthorization, authorization)
+		recorder := httptest.NewRecorder()
+		assert.NotPanics(t, func() { e.ServeHTTP(recorder, request) }, authorization)
+		assert.Equal(t, http.StatusOK, recorder.Code, authorization)
+
+		entry := map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal(output.Bytes(), &entry), authorization)
+		requestLog := entry["request"].(map[string]interface{})
+		assert.Equal(t, "[REDACTED]", requestLog["client"], authorization)
+	}
+}
@@ -0,0 +1,142 @@
+package middlewares
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/context"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+)
+
+type ContentNegotiationConfig struct {
+	// Media types accepted on the request body ("type/*" ranges are allowed). Only JSON is accepted when empty
+	Consumes []string
+	// Media types the route can produce, in order of preference. Only JSON is produced when empty
+	Produces []string
+}
+
+// ContentNegotiation validates the request Content-Type (415) and negotiates the response media type with the
+// Accept header (406). Errors are rendered as application/problem+json when the client accepts it
+func ContentNegotiation(config ContentNegotiationConfig) echo.MiddlewareFunc {
+	if len(config.Consumes) == 0 {
+		config.Consumes = []string{echo.MIMEApplicationJSON}
+	}
+	if len(config.Produces) == 0 {
+		config.Produces = []string{echo.MIMEApplicationJSON}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			accepted := parseAccept(c.Request().Header.Get(echo.HeaderAccept))
+			if acceptsMediaType(accepted, responses.MIMEApplicationProblemJSON) {
+				c.Set(context.ErrorMediaTypeKey, responses.MIMEApplicationProblemJSON)
+			}
+
+			if hasBody(c) {
+				contentType := c.Request().Header.Get(echo.HeaderContentType)
+				if contentType == "" {
+					responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByIssue(faults.MissingContentType, responses.Header, echo.HeaderContentType, "")
+					return context.WriteResponseMessage(c, responseMessage)
+				}
+				mediaType, _, err := mime.ParseMediaType(contentType)
+				if err != nil || !matchesAny(config.Consumes, mediaType) {
+					responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByIssue(faults.InvalidContentType, responses.Header, echo.HeaderContentType, "")
+					return context.WriteResponseMessage(c, responseMessage)
+				}
+			}
+
+			mediaType, ok := negotiate(accepted, config.Produces)
+			if !ok {
+				responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByIssue(faults.InvalidAcceptType, responses.Header, echo.HeaderAccept, "")
+				return context.WriteResponseMessage(c, responseMessage)
+			}
+			c.Set(context.ResponseMediaTypeKey, mediaType)
+			return next(c)
+		}
+	}
+}
+
+type acceptedMediaType struct {
+	mediaType string
+	quality   float64
+}
+
+// parseAccept returns the media ranges of the Accept header by descending quality. A missing header accepts anything
+func parseAccept(header string) []acceptedMediaType {
+	if strings.TrimSpace(header) == "" {
+		return []acceptedMediaType{{mediaType: "*/*", quality: 1}}
+	}
+
+	var accepted []acceptedMediaType
+	for _, value := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			accepted = append(accepted, acceptedMediaType{mediaType: mediaType, quality: quality})
+		}
+	}
+	sort.SliceStable(accepted, func(i, j int) bool {
+		return accepted[i].quality > accepted[j].quality
+	})
+	return accepted
+}
+
+// negotiate picks the first produced media type matching the most preferred accepted range
+func negotiate(accepted []acceptedMediaType, produces []string) (string, bool) {
+	for _, acceptedType := range accepted {
+		for _, produced := range produces {
+			if matchesMediaRange(acceptedType.mediaType, produced) {
+				return produced, true
+			}
+		}
+	}
+	return "", false
+}
+
+// acceptsMediaType reports whether the media type is explicitly listed (wildcards are not considered)
+func acceptsMediaType(accepted []acceptedMediaType, mediaType string) bool {
+	for _, acceptedType := range accepted {
+		if acceptedType.mediaType == mediaType {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(mediaRanges []string, mediaType string) bool {
+	for _, mediaRange := range mediaRanges {
+		if matchesMediaRange(mediaRange, mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesMediaRange(mediaRange string, mediaType string) bool {
+	mediaRange = strings.ToLower(mediaRange)
+	mediaType = strings.ToLower(mediaType)
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	if strings.HasSuffix(mediaRange, "/*") {
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(mediaRange, "*"))
+	}
+	return false
+}
+
+func hasBody(c echo.Context) bool {
+	request := c.Request()
+	return request.ContentLength > 0 || request.ContentLength == -1
+}
@@ -0,0 +1,104 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/context"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentNegotiation(t *testing.T) {
+	e := echo.New()
+	e.Use(ContentNegotiation(ContentNegotiationConfig{
+		Produces: []string{echo.MIMEApplicationJSON, "text/csv"},
+	}))
+	e.POST("/v1/reports", func(c echo.Context) error {
+		return c.String(http.StatusOK, context.ResponseMediaType(c))
+	})
+
+	tests := []struct {
+		name        string
+		contentType string
+		accept      string
+		status      int
+		body        string
+		issue       faults.Issue
+	}{
+		{"default", echo.MIMEApplicationJSON, "", http.StatusOK, echo.MIMEApplicationJSON, ""},
+		{"charset parameter", "application/json; charset=utf-8", "text/*", http.StatusOK, "text/csv", ""},
+		{"quality", echo.MIMEApplicationJSON, "application/json;q=0.5, text/csv", http.StatusOK, "text/csv", ""},
+		{"missing content type", "", "", http.StatusUnsupportedMediaType, "", faults.MissingContentType},
+		{"invalid content type", "text/plain", "", http.StatusUnsupportedMediaType, "", faults.InvalidContentType},
+		{"not acceptable", echo.MIMEApplicationJSON, "application/xml", http.StatusNotAcceptable, "", faults.InvalidAcceptType},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodPost, "/v1/reports", strings.NewReader(`{}`))
+		if test.contentType != "" {
+			request.Header.Set(echo.HeaderContentType, test.contentType)
+		}
+		request.Header.Set(echo.HeaderAccept, test.accept)
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+
+		assert.Equal(t, test.status, recorder.Code, test.name)
+		if test.issue == "" {
+			assert.Equal(t, test.body, recorder.Body.String(), test.name)
+			continue
+		}
+		responseMessage := decodeResponseMessage(t, recorder)
+		assert.Equal(t, string(test.issue), responseMessage.Details[0].Issue, test.name)
+	}
+}
+
+func TestContentNegotiationProblemJSON(t *testing.T) {
+	e := echo.New()
+	e.Use(ContentNegotiation(ContentNegotiationConfig{}))
+	e.POST("/v1/users", func(c echo.Context) error {
+		return c.NoContent(http.StatusCreated)
+	})
+
+	request := httptest.NewRequest(http.MethodPost, "/v1/users", strings.NewReader(`{}`))
+	request.Header.Set(echo.HeaderContentType, "text/plain")
+	request.Header.Set(echo.HeaderAccept, "application/json, application/problem+json")
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	problem := responses.Problem{}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+	assert.Equal(t, responses.MIMEApplicationProblemJSON, recorder.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, http.StatusUnsupportedMediaType, problem.Status)
+	assert.Equal(t, string(faults.UnsupportedMediaType), problem.ErrorCode)
+	assert.Equal(t, "/v1/users", problem.Instance)
+	assert.Equal(t, string(faults.InvalidContentType), problem.Details[0].Issue)
+}
+
+func TestMiddlewareErrorsProblemJSON(t *testing.T) {
+	initTestLogger(t)
+
+	e := echo.New()
+	e.Use(ContentNegotiation(ContentNegotiationConfig{}))
+	e.Use(Tenant())
+	e.GET("/v1/users", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	request.Header.Set(echo.HeaderAccept, "application/json, application/problem+json")
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	problem := responses.Problem{}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Equal(t, responses.MIMEApplicationProblemJSON, recorder.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, http.StatusForbidden, problem.Status)
+}
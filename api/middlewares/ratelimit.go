@@ -0,0 +1,94 @@
+package middlewares
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/context"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/auth"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/logger"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/ratelimit"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tenant"
+)
+
+type RateLimitConfig struct {
+	Policy ratelimit.Policy
+	// The in-memory store (per instance) is used when there is none
+	Store ratelimit.IStore
+}
+
+// RateLimit is NewRateLimit for the policies known to be valid: it panics when the policy is invalid
+func RateLimit(config RateLimitConfig) echo.MiddlewareFunc {
+	middleware, err := NewRateLimit(config)
+	if err != nil {
+		panic(err)
+	}
+	return middleware
+}
+
+// NewRateLimit rejects the requests exceeding the policy with the TOO_MANY_REQUESTS response and the Retry-After
+// header. Requests keyed by user or tenant fall back to the client IP when they are not authenticated. An invalid
+// policy is returned as an error
+func NewRateLimit(config RateLimitConfig) (echo.MiddlewareFunc, error) {
+	if err := config.Policy.Validate(); err != nil {
+		return nil, fmt.Errorf("rate limit policy [%s]: %w", config.Policy.Name, err)
+	}
+	if config.Store == nil {
+		config.Store = ratelimit.NewMemoryStore()
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := fmt.Sprintf("%s:%s %s:%s", config.Policy.Name, c.Request().Method, c.Path(), rateLimitKey(c, config.Policy.Key))
+
+			result, err := config.Store.Take(key, config.Policy, time.Now())
+			if err != nil {
+				// Fail open: the store being unavailable must not take the service down
+				logger.GetLoggerWithContext(c.Request().Context()).Errorf("Error trying to apply the rate limit [%s]: %v", config.Policy.Name, err)
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+			if !result.Allowed {
+				header.Set("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+				logger.GetLoggerWithContext(c.Request().Context()).Infof("Rate limit [%s] exceeded: %s", config.Policy.Name, key)
+				responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByErrorCode(faults.TooManyRequests)
+				return context.WriteResponseMessage(c, responseMessage)
+			}
+			return next(c)
+		}
+	}, nil
+}
+
+func rateLimitKey(c echo.Context, keyType ratelimit.KeyType) string {
+	ctx := c.Request().Context()
+	identity, authenticated := auth.IdentityFromContext(ctx)
+
+	switch keyType {
+	case ratelimit.ByUser:
+		if authenticated && identity.Subject != "" {
+			return "user:" + identity.Subject
+		}
+	case ratelimit.ByTenant:
+		if tenantId, ok := tenant.FromContext(ctx); ok {
+			return "tenant:" + tenantId
+		}
+		if authenticated && identity.TenantId != "" {
+			return "tenant:" + identity.TenantId
+		}
+	}
+	return "ip:" + c.RealIP()
+}
+
+func ceilSeconds(duration time.Duration) int {
+	return int(math.Ceil(duration.Seconds()))
+}
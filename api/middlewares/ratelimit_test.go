@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRateLimitDefaultStore(t *testing.T) {
+	initTestLogger(t)
+
+	middleware, err := NewRateLimit(RateLimitConfig{Policy: ratelimit.Policy{
+		Name:      "default",
+		Algorithm: ratelimit.SlidingWindow,
+		Key:       ratelimit.ByIP,
+		Limit:     1,
+		Period:    time.Minute,
+	}})
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.Use(middleware)
+	e.GET("/v1/periods", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	first := httptest.NewRecorder()
+	e.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/v1/periods", nil))
+	assert.Equal(t, http.StatusOK, first.Code)
+
+	second := httptest.NewRecorder()
+	e.ServeHTTP(second, httptest.NewRequest(http.MethodGet, "/v1/periods", nil))
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+	assert.Contains(t, second.Body.String(), string(faults.TooManyRequests))
+	assert.NotEmpty(t, second.Header().Get("Retry-After"))
+}
+
+func TestNewRateLimitInvalidPolicy(t *testing.T) {
+	middleware, err := NewRateLimit(RateLimitConfig{Policy: ratelimit.Policy{Name: "invalid"}})
+	assert.Nil(t, middleware)
+	assert.True(t, errors.Is(err, ratelimit.ErrInvalidPolicy))
+
+	assert.Panics(t, func() {
+		RateLimit(RateLimitConfig{Policy: ratelimit.Policy{Name: "invalid"}})
+	})
+}
@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/context"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/logger"
+)
+
+// Recover turns a panic inside the handler chain into the INTERNAL_SERVER_ERROR response, logging the panic value
+// and the stack (the request id is added by the logger from the request context)
+func Recover() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+
+				logger.GetLoggerWithContext(c.Request().Context()).
+					WithField("stack", string(debug.Stack())).
+					Errorf("Panic recovered: %v", recovered)
+
+				if c.Response().Committed {
+					err = fmt.Errorf("panic recovered after the response was committed: %v", recovered)
+					return
+				}
+				responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByErrorCode(faults.InternalServerError)
+				err = context.WriteResponseMessage(c, responseMessage)
+			}()
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecover(t *testing.T) {
+	initTestLogger(t)
+
+	e := echo.New()
+	e.Use(Recover())
+	e.GET("/v1/panic", func(c echo.Context) error {
+		panic("unexpected")
+	})
+
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Equal(t, string(faults.InternalServerError), decodeResponseMessage(t, recorder).ErrorCode)
+}
+
+func TestHTTPErrorHandler(t *testing.T) {
+	initTestLogger(t)
+
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.GET("/v1/users", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	e.POST("/v1/users", func(c echo.Context) error {
+		return echo.ErrUnsupportedMediaType
+	})
+	e.PUT("/v1/users", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusConflict, "duplicated")
+	})
+	e.PATCH("/v1/users", func(c echo.Context) error {
+		return echo.ErrStatusRequestEntityTooLarge
+	})
+	e.OPTIONS("/v1/users", func(c echo.Context) error {
+		return echo.ErrNotImplemented
+	})
+
+	tests := []struct {
+		method    string
+		uri       string
+		status    int
+		errorCode faults.ErrorCode
+		issue     faults.Issue
+	}{
+		{http.MethodGet, "/v1/unknown", http.StatusNotFound, faults.ResourceNotFound, faults.InvalidURI},
+		{http.MethodDelete, "/v1/users", http.StatusMethodNotAllowed, faults.MethodNotAllowed, faults.MethodNotSupported},
+		{http.MethodPost, "/v1/users", http.StatusUnsupportedMediaType, faults.UnsupportedMediaType, faults.MissingContentType},
+		{http.MethodPut, "/v1/users", http.StatusConflict, faults.Conflict, ""},
+		{http.MethodPatch, "/v1/users", http.StatusRequestEntityTooLarge, faults.InvalidRequestSyntax, ""},
+		{http.MethodOptions, "/v1/users", http.StatusNotImplemented, faults.InternalServerError, ""},
+	}
+
+	for _, test := range tests {
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, httptest.NewRequest(test.method, test.uri, strings.NewReader("")))
+
+		responseMessage := decodeResponseMessage(t, recorder)
+		assert.Equal(t, test.status, recorder.Code, test.uri)
+		assert.Equal(t, string(test.errorCode), responseMessage.ErrorCode, test.uri)
+		if test.issue != "" {
+			assert.Equal(t, string(test.issue), responseMessage.Details[0].Issue, test.uri)
+		}
+	}
+}
+
+func decodeResponseMessage(t *testing.T, recorder *httptest.ResponseRecorder) responses.ResponseMessage {
+	responseMessage := responses.ResponseMessage{}
+	assert.Nil(t, json.Unmarshal(recorder.Body.Bytes(), &responseMessage))
+	return responseMessage
+}
+
+// The application logger is initialized from the settings on its first use
+func initTestLogger(t *testing.T) {
+	settings.Config.LogLevel = "error"
+	settings.Config.LogAppFile = filepath.Join(t.TempDir(), "app.log")
+}
@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/correlation"
+	uuid "github.com/satori/go.uuid"
+)
+
+// RequestID reads (or generates) the request and correlation ids, stores them in the request context
+// and echoes them on the response headers
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			request := c.Request()
+
+			requestId := request.Header.Get(correlation.RequestIDHeader)
+			if !isValidID(requestId) {
+				requestId = uuid.NewV4().String()
+			}
+
+			// A new flow starts on this request when the caller did not send a correlation id
+			correlationId := request.Header.Get(correlation.CorrelationIDHeader)
+			if !isValidID(correlationId) {
+				correlationId = requestId
+			}
+
+			ids := correlation.IDs{
+				RequestID:     requestId,
+				CorrelationID: correlationId,
+			}
+
+			c.Set("request_id", requestId)
+			c.Set("correlation_id", correlationId)
+			c.SetRequest(request.WithContext(correlation.NewContext(request.Context(), ids)))
+
+			c.Response().Header().Set(correlation.RequestIDHeader, requestId)
+			c.Response().Header().Set(correlation.CorrelationIDHeader, correlationId)
+
+			return next(c)
+		}
+	}
+}
+
+// isValidID discards empty, oversized or non printable ids sent by the clients, since they end up in the logs
+func isValidID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, character := range id {
+		if character < '!' || character > '~' {
+			return false
+		}
+	}
+	return true
+}
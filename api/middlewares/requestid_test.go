@@ -0,0 +1,51 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/correlation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	e := echo.New()
+	e.Use(RequestID())
+
+	var ids correlation.IDs
+	e.GET("/", func(c echo.Context) error {
+		ids, _ = correlation.FromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	// New flow
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	assert.NotEmpty(t, ids.RequestID)
+	assert.Equal(t, ids.RequestID, ids.CorrelationID)
+	assert.Equal(t, ids.RequestID, recorder.Header().Get(correlation.RequestIDHeader))
+	assert.Equal(t, ids.CorrelationID, recorder.Header().Get(correlation.CorrelationIDHeader))
+
+	// Propagated flow
+	request = httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(correlation.RequestIDHeader, "request-1")
+	request.Header.Set(correlation.CorrelationIDHeader, "correlation-1")
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	assert.Equal(t, correlation.IDs{RequestID: "request-1", CorrelationID: "correlation-1"}, ids)
+	assert.Equal(t, "request-1", recorder.Header().Get(correlation.RequestIDHeader))
+
+	// Invalid ids are replaced
+	request = httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(correlation.RequestIDHeader, "request\n1")
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	assert.NotEqual(t, "request\n1", ids.RequestID)
+	assert.NotEmpty(t, ids.RequestID)
+}
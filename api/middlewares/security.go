@@ -0,0 +1,99 @@
+package middlewares
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/marcelofelixsalgado/financial-commons/settings"
+)
+
+// Environment defaults of the security headers. The APIs only answer JSON, so the CSP denies everything else
+const (
+	developmentContentSecurityPolicy = "default-src 'self'; frame-ancestors 'none'"
+	productionContentSecurityPolicy  = "default-src 'none'; frame-ancestors 'none'"
+	defaultFrameOptions              = "DENY"
+	developmentReferrerPolicy        = "strict-origin-when-cross-origin"
+	productionReferrerPolicy         = "no-referrer"
+)
+
+// CORS applies the CORS policy configured on the settings
+func CORS() echo.MiddlewareFunc {
+	return middleware.CORSWithConfig(NewCORSConfig(settings.Config))
+}
+
+// NewCORSConfig builds the CORS policy from the settings. Any origin is allowed on development when no origin is
+// configured; on the other environments only the configured origins are allowed. Panics when there is no origin outside
+// development, since echo would allow any origin, and when credentials are allowed for any origin, since browsers would
+// send the session cookies to every site
+func NewCORSConfig(config settings.ConfigType) middleware.CORSConfig {
+	allowOrigins := config.CORSAllowOrigins
+	if len(allowOrigins) == 0 {
+		if !isDevelopment(config.Environment) {
+			panic("CORS origins are required outside development: set CORS_ALLOW_ORIGINS")
+		}
+		allowOrigins = []string{"*"}
+	}
+	if config.CORSAllowCredentials {
+		for _, origin := range allowOrigins {
+			if origin == "*" {
+				panic("CORS credentials cannot be allowed for any origin: set CORS_ALLOW_ORIGINS")
+			}
+		}
+	}
+
+	return middleware.CORSConfig{
+		AllowOrigins:     allowOrigins,
+		AllowMethods:     config.CORSAllowMethods,
+		AllowHeaders:     config.CORSAllowHeaders,
+		ExposeHeaders:    config.CORSExposeHeaders,
+		AllowCredentials: config.CORSAllowCredentials,
+		MaxAge:           config.CORSMaxAge,
+	}
+}
+
+// SecurityHeaders sets HSTS, X-Content-Type-Options, X-Frame-Options, Content-Security-Policy and Referrer-Policy
+func SecurityHeaders() echo.MiddlewareFunc {
+	return middleware.SecureWithConfig(NewSecureConfig(settings.Config))
+}
+
+// NewSecureConfig builds the security headers from the settings, using the environment defaults for the empty values
+func NewSecureConfig(config settings.ConfigType) middleware.SecureConfig {
+	development := isDevelopment(config.Environment)
+
+	secureConfig := middleware.SecureConfig{
+		ContentTypeNosniff:    "nosniff",
+		XFrameOptions:         config.FrameOptions,
+		ContentSecurityPolicy: config.ContentSecurityPolicy,
+		ReferrerPolicy:        config.ReferrerPolicy,
+	}
+
+	if secureConfig.XFrameOptions == "" {
+		secureConfig.XFrameOptions = defaultFrameOptions
+	}
+	if secureConfig.ContentSecurityPolicy == "" {
+		secureConfig.ContentSecurityPolicy = productionContentSecurityPolicy
+		if development {
+			secureConfig.ContentSecurityPolicy = developmentContentSecurityPolicy
+		}
+	}
+	if secureConfig.ReferrerPolicy == "" {
+		secureConfig.ReferrerPolicy = productionReferrerPolicy
+		if development {
+			secureConfig.ReferrerPolicy = developmentReferrerPolicy
+		}
+	}
+	// HSTS would pin localhost to HTTPS on the developer browsers
+	if !development {
+		secureConfig.HSTSMaxAge = config.HSTSMaxAge
+	}
+	return secureConfig
+}
+
+func isDevelopment(environment string) bool {
+	switch strings.ToLower(environment) {
+	case "development", "dev", "local", "test":
+		return true
+	}
+	return false
+}
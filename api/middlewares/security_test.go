@@ -0,0 +1,98 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/marcelofelixsalgado/financial-commons/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCORSConfig(t *testing.T) {
+	development := NewCORSConfig(settings.ConfigType{Environment: "development"})
+	assert.Equal(t, []string{"*"}, development.AllowOrigins)
+
+	production := NewCORSConfig(settings.ConfigType{Environment: "production", CORSAllowOrigins: []string{"https://app.financial.com"}, CORSMaxAge: 600})
+	assert.Equal(t, []string{"https://app.financial.com"}, production.AllowOrigins)
+	assert.Equal(t, 600, production.MaxAge)
+
+	assert.Panics(t, func() {
+		NewCORSConfig(settings.ConfigType{Environment: "production"})
+	})
+	assert.Panics(t, func() {
+		NewCORSConfig(settings.ConfigType{})
+	})
+
+	assert.Panics(t, func() {
+		NewCORSConfig(settings.ConfigType{Environment: "development", CORSAllowCredentials: true})
+	})
+}
+
+func TestCORSPreflight(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.CORSWithConfig(NewCORSConfig(settings.ConfigType{
+		Environment:          "production",
+		CORSAllowOrigins:     []string{"https://app.financial.com"},
+		CORSAllowMethods:     []string{http.MethodGet, http.MethodPost},
+		CORSAllowHeaders:     []string{echo.HeaderAuthorization},
+		CORSAllowCredentials: true,
+		CORSMaxAge:           600,
+	})))
+	e.POST("/v1/users", func(c echo.Context) error {
+		return c.NoContent(http.StatusCreated)
+	})
+
+	request := httptest.NewRequest(http.MethodOptions, "/v1/users", nil)
+	request.Header.Set(echo.HeaderOrigin, "https://app.financial.com")
+	request.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPost)
+	recorder := httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	assert.Equal(t, http.StatusNoContent, recorder.Code)
+	assert.Equal(t, "https://app.financial.com", recorder.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, "true", recorder.Header().Get(echo.HeaderAccessControlAllowCredentials))
+	assert.Equal(t, "600", recorder.Header().Get(echo.HeaderAccessControlMaxAge))
+
+	request = httptest.NewRequest(http.MethodOptions, "/v1/users", nil)
+	request.Header.Set(echo.HeaderOrigin, "https://evil.com")
+	request.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPost)
+	recorder = httptest.NewRecorder()
+	e.ServeHTTP(recorder, request)
+
+	assert.Empty(t, recorder.Header().Get(echo.HeaderAccessControlAllowOrigin))
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	tests := []struct {
+		environment string
+		hsts        string
+		csp         string
+		referrer    string
+	}{
+		{"development", "", developmentContentSecurityPolicy, developmentReferrerPolicy},
+		{"production", "max-age=31536000; includeSubdomains", productionContentSecurityPolicy, productionReferrerPolicy},
+	}
+
+	for _, test := range tests {
+		e := echo.New()
+		e.Use(middleware.SecureWithConfig(NewSecureConfig(settings.ConfigType{Environment: test.environment, HSTSMaxAge: 31536000})))
+		e.GET("/v1/users", func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		})
+
+		request := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+		request.Header.Set(echo.HeaderXForwardedProto, "https")
+		recorder := httptest.NewRecorder()
+		e.ServeHTTP(recorder, request)
+
+		header := recorder.Header()
+		assert.Equal(t, "nosniff", header.Get(echo.HeaderXContentTypeOptions), test.environment)
+		assert.Equal(t, "DENY", header.Get(echo.HeaderXFrameOptions), test.environment)
+		assert.Equal(t, test.hsts, header.Get(echo.HeaderStrictTransportSecurity), test.environment)
+		assert.Equal(t, test.csp, header.Get(echo.HeaderContentSecurityPolicy), test.environment)
+		assert.Equal(t, test.referrer, header.Get(echo.HeaderReferrerPolicy), test.environment)
+	}
+}
@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/context"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/auth"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/logger"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tenant"
+)
+
+// Tenant resolves the tenant from the authenticated identity (or the token claims) and stores it
+// in the request context. Requests without a tenant are rejected.
+func Tenant() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			tenantId, err := resolveTenant(c)
+			if err != nil || tenantId == "" {
+				logger.GetLoggerWithContext(c.Request().Context()).Infof("Tenant resolution error: %v", err)
+				responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByIssue(faults.PermissionDenied, "", "", "")
+				return context.WriteResponseMessage(c, responseMessage)
+			}
+
+			c.Set(tenant.ClaimName, tenantId)
+			c.SetRequest(c.Request().WithContext(tenant.NewContext(c.Request().Context(), tenantId)))
+			return next(c)
+		}
+	}
+}
+
+func resolveTenant(c echo.Context) (string, error) {
+	if identity, ok := auth.IdentityFromContext(c.Request().Context()); ok {
+		return identity.TenantId, nil
+	}
+	return auth.ExtractTenantId(c.Request())
+}
@@ -0,0 +1,72 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	apicontext "github.com/marcelofelixsalgado/financial-commons/api/context"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/logger"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/deadline"
+	"github.com/marcelofelixsalgado/financial-commons/settings"
+)
+
+type TimeoutConfig struct {
+	// Time to answer the request. settings.Config.RequestTimeout (seconds) is used when zero
+	Timeout time.Duration
+	// Error answered when the deadline expires. GATEWAY_TIMEOUT when empty
+	ErrorCode faults.ErrorCode
+}
+
+func Timeout() echo.MiddlewareFunc {
+	return TimeoutWithConfig(TimeoutConfig{})
+}
+
+// TimeoutWithConfig sets a deadline on the request context. The deadline is shortened to the time remaining sent by the
+// caller (X-Request-Timeout header), and requests whose caller already gave up are answered with SERVICE_UNAVAILABLE
+// without running the handler.
+//
+// The handler is not interrupted: it must honour the context (as the database and upstream helpers do). When it
+// returns after the deadline without having answered, the configured error is sent
+func TimeoutWithConfig(config TimeoutConfig) echo.MiddlewareFunc {
+	if config.Timeout <= 0 {
+		config.Timeout = time.Duration(settings.Config.RequestTimeout) * time.Second
+	}
+	if config.ErrorCode == "" {
+		config.ErrorCode = faults.GatewayTimeout
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			timeout := config.Timeout
+			if remaining, ok := deadline.FromHeader(c.Request().Header); ok {
+				if remaining <= 0 {
+					responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByErrorCode(faults.ServiceUnavailable)
+					return apicontext.WriteResponseMessage(c, responseMessage)
+				}
+				if timeout <= 0 || remaining < timeout {
+					timeout = remaining
+				}
+			}
+			if timeout <= 0 {
+				return next(c)
+			}
+
+			ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			err := next(c)
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) || c.Response().Committed {
+				return err
+			}
+
+			logger.GetLoggerWithContext(ctx).Warnf("Request deadline of %v expired: %v", timeout, err)
+			responseMessage := responses.NewResponseMessageWithContext(c.Request().Context()).AddMessageByErrorCode(config.ErrorCode)
+			return apicontext.WriteResponseMessage(c, responseMessage)
+		}
+	}
+}
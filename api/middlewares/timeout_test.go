@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/deadline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	initTestLogger(t)
+
+	e := echo.New()
+	e.Use(TimeoutWithConfig(TimeoutConfig{Timeout: 20 * time.Millisecond}))
+	e.GET("/v1/slow", func(c echo.Context) error {
+		<-c.Request().Context().Done()
+		return c.Request().Context().Err()
+	})
+	e.GET("/v1/fast", func(c echo.Context) error {
+		_, ok := c.Request().Context().Deadline()
+		assert.True(t, ok)
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		uri       string
+		remaining string
+		status    int
+		errorCode faults.ErrorCode
+	}{
+		{"/v1/slow", "", http.StatusGatewayTimeout, faults.GatewayTimeout},
+		{"/v1/slow", "5", http.StatusGatewayTimeout, faults.GatewayTimeout},
+		{"/v1/fast", "", http.StatusNoContent, ""},
+		{"/v1/fast", "0", http.StatusServiceUnavailable, faults.ServiceUnavailable},
+	}
+
+	for _, test := range tests {
+		request := httptest.NewRequest(http.MethodGet, test.uri, nil)
+		if test.remaining != "" {
+			request.Header.Set(deadline.Header, test.remaining)
+		}
+		recorder := httptest.NewRecorder()
+		start := time.Now()
+		e.ServeHTTP(recorder, request)
+
+		assert.Equal(t, test.status, recorder.Code, test.uri)
+		assert.Less(t, time.Since(start), time.Second, test.uri)
+		if test.errorCode != "" {
+			assert.Equal(t, string(test.errorCode), decodeResponseMessage(t, recorder).ErrorCode, test.uri)
+		}
+	}
+}
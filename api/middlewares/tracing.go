@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tracing"
+)
+
+// Tracing starts a server span for each request, continuing the trace received on the traceparent header
+func Tracing() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			request := c.Request()
+
+			ctx := tracing.Extract(request.Context(), request.Header)
+			ctx, span := tracing.StartSpan(ctx, fmt.Sprintf("%s %s", request.Method, c.Path()), tracing.SpanKindServer)
+			defer span.End()
+
+			span.SetAttribute("http.method", request.Method)
+			span.SetAttribute("http.route", c.Path())
+			span.SetAttribute("http.target", request.RequestURI)
+			span.SetAttribute("http.user_agent", request.UserAgent())
+			span.SetAttribute("net.peer.ip", c.RealIP())
+
+			c.SetRequest(request.WithContext(ctx))
+
+			err := next(c)
+
+			// The error response is written by the echo error handler after the middlewares return
+			status := c.Response().Status
+			if err != nil {
+				status = http.StatusInternalServerError
+				if httpError, ok := err.(*echo.HTTPError); ok {
+					status = httpError.Code
+				}
+			}
+
+			span.SetAttribute("http.status_code", status)
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(tracing.StatusError, http.StatusText(status))
+				span.RecordError(err)
+			}
+			return err
+		}
+	}
+}
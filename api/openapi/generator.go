@@ -0,0 +1,240 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/controllers"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/auth"
+)
+
+const (
+	bearerSecurityScheme = "bearerAuth"
+	apiKeySecurityScheme = "apiKeyAuth"
+)
+
+var pathParameterRegexp = regexp.MustCompile(`:([^/]+)`)
+
+type generator struct {
+	document *Document
+}
+
+// Generate builds the document of the routes. The error responses are derived from the fault catalog, according to
+// the middlewares the router applies to each route (authentication, rate limit, content negotiation...)
+func Generate(info Info, groups ...controllers.RouteGroup) *Document {
+	generator := &generator{
+		document: &Document{
+			OpenAPI: Version,
+			Info:    info,
+			Paths:   make(map[string]PathItem),
+			Components: Components{
+				Schemas:   make(map[string]*Schema),
+				Responses: make(map[string]*Response),
+				SecuritySchemes: map[string]SecurityScheme{
+					bearerSecurityScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+					apiKeySecurityScheme: {Type: "apiKey", In: "header", Name: auth.APIKeyHeader},
+				},
+			},
+		},
+	}
+	generator.addErrorResponses()
+
+	for _, group := range groups {
+		for _, route := range group.Routes {
+			generator.addOperation(group.RoutePath(route), route)
+		}
+	}
+	return generator.document
+}
+
+// NewRoute serves the document as JSON
+func NewRoute(uri string, document *Document) controllers.Route {
+	return controllers.Route{
+		URI:    uri,
+		Method: http.MethodGet,
+		Function: func(c echo.Context) error {
+			return c.JSON(http.StatusOK, document)
+		},
+		Summary: "OpenAPI document",
+	}
+}
+
+func (generator *generator) addOperation(routePath string, route controllers.Route) {
+	method := strings.ToUpper(route.Method)
+	path := pathParameterRegexp.ReplaceAllString(routePath, "{$1}")
+
+	operation := &Operation{
+		Summary:     route.Summary,
+		Tags:        route.Tags,
+		OperationID: operationID(method, routePath),
+		Responses:   make(map[string]*Response),
+		Deprecated:  route.Deprecation != nil,
+	}
+
+	for _, match := range pathParameterRegexp.FindAllStringSubmatch(routePath, -1) {
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	hasBody := route.Request != nil || method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+	if route.Request != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  generator.content(route.Consumes, route.Request),
+		}
+	}
+
+	status := route.ResponseStatus
+	if status == 0 {
+		status = http.StatusOK
+	}
+	operation.Responses[strconv.Itoa(status)] = &Response{
+		Description: http.StatusText(status),
+		Content:     generator.content(route.Produces, route.Response),
+	}
+
+	errorCodes := []faults.ErrorCode{faults.NotAcceptable, faults.InternalServerError, faults.GatewayTimeout}
+	if hasBody {
+		errorCodes = append(errorCodes, faults.InvalidRequestSyntax, faults.UnprocessableEntity, faults.UnsupportedMediaType)
+	}
+	if route.RequiresAuthentication {
+		errorCodes = append(errorCodes, faults.NotAuthorized)
+		operation.Security = security(route.AuthenticationSchemes)
+	}
+	if strings.Contains(routePath, ":") {
+		errorCodes = append(errorCodes, faults.ResourceNotFound)
+	}
+	if route.RateLimit != nil {
+		errorCodes = append(errorCodes, faults.TooManyRequests)
+	}
+	for _, errorCode := range errorCodes {
+		if reference, err := faults.FindByErrorCode(errorCode); err == nil {
+			operation.Responses[strconv.Itoa(reference.HttpStatusCode)] = &Response{Ref: "#/components/responses/" + string(errorCode)}
+		}
+	}
+
+	pathItem, ok := generator.document.Paths[path]
+	if !ok {
+		pathItem = make(PathItem)
+		generator.document.Paths[path] = pathItem
+	}
+	pathItem[strings.ToLower(method)] = operation
+}
+
+func (generator *generator) content(mediaTypes []string, body interface{}) map[string]MediaType {
+	if body == nil {
+		return nil
+	}
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{echo.MIMEApplicationJSON}
+	}
+
+	schema := generator.schemaOf(reflect.TypeOf(body))
+	content := make(map[string]MediaType)
+	for _, mediaType := range mediaTypes {
+		content[mediaType] = MediaType{Schema: schema}
+	}
+	return content
+}
+
+// addErrorResponses documents each catalog error code as a ResponseMessage restricted to its own issues
+func (generator *generator) addErrorResponses() {
+	locations := []string{string(responses.Body), string(responses.Header), string(responses.QueryParameter), string(responses.PathParameter)}
+
+	for _, reference := range faults.List() {
+		var issues []string
+		for _, detail := range reference.Details {
+			issues = append(issues, string(detail.Issue))
+		}
+		sort.Strings(issues)
+
+		schema := &Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"error_code": {Type: "string", Enum: []string{string(reference.ErrorCode)}},
+				"message":    {Type: "string"},
+			},
+			Required: []string{"error_code", "message"},
+		}
+		if len(issues) > 0 {
+			schema.Properties["details"] = &Schema{
+				Type: "array",
+				Items: &Schema{
+					Type: "object",
+					Properties: map[string]*Schema{
+						"issue":       {Type: "string", Enum: issues},
+						"description": {Type: "string"},
+						"location":    {Type: "string", Enum: locations},
+						"field":       {Type: "string"},
+						"value":       {Type: "string"},
+					},
+					Required: []string{"issue", "description"},
+				},
+			}
+		}
+
+		example := map[string]string{"error_code": string(reference.ErrorCode), "message": reference.Message}
+		generator.document.Components.Responses[string(reference.ErrorCode)] = &Response{
+			Description: reference.Message,
+			Content: map[string]MediaType{
+				echo.MIMEApplicationJSON:             {Schema: schema, Example: example},
+				responses.MIMEApplicationProblemJSON: {Schema: problemSchema(schema)},
+			},
+		}
+	}
+}
+
+func problemSchema(responseMessage *Schema) *Schema {
+	properties := map[string]*Schema{
+		"type":     {Type: "string"},
+		"title":    {Type: "string"},
+		"status":   {Type: "integer", Format: "int32"},
+		"detail":   {Type: "string"},
+		"instance": {Type: "string"},
+	}
+	properties["error_code"] = responseMessage.Properties["error_code"]
+	if details, ok := responseMessage.Properties["details"]; ok {
+		properties["details"] = details
+	}
+	return &Schema{Type: "object", Properties: properties, Required: []string{"type", "title", "status", "error_code"}}
+}
+
+func security(schemes []auth.Scheme) []map[string][]string {
+	if len(schemes) == 0 {
+		schemes = []auth.Scheme{auth.BearerScheme}
+	}
+
+	var requirements []map[string][]string
+	for _, scheme := range schemes {
+		switch scheme {
+		case auth.BearerScheme:
+			requirements = append(requirements, map[string][]string{bearerSecurityScheme: {}})
+		case auth.APIKeyScheme:
+			requirements = append(requirements, map[string][]string{apiKeySecurityScheme: {}})
+		}
+	}
+	return requirements
+}
+
+// operationID builds a unique id from the method and path, e.g. get_v1_users_id
+func operationID(method string, routePath string) string {
+	var parts []string
+	for _, part := range strings.Split(routePath, "/") {
+		part = strings.Trim(part, ":*")
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.ToLower(method) + "_" + strings.Join(parts, "_")
+}
@@ -0,0 +1,121 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/controllers"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPackage = "github.com.marcelofelixsalgado.financial-commons.api.openapi."
+
+type address struct {
+	City string `json:"city"`
+}
+
+type audit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type user struct {
+	audit
+	Id       string            `json:"id"`
+	Name     string            `json:"name"`
+	Age      int               `json:"age,omitempty"`
+	Address  *address          `json:"address"`
+	Tags     []string          `json:"tags,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Password string            `json:"-"`
+}
+
+type amount struct{}
+
+func (amount) MarshalJSON() ([]byte, error) {
+	return []byte(`"10.00"`), nil
+}
+
+// Page is named like responses.Page
+type Page struct {
+	Id       uuid.UUID      `json:"id"`
+	Amount   amount         `json:"amount"`
+	Checksum [4]byte        `json:"checksum"`
+	Page     responses.Page `json:"page"`
+}
+
+func TestGenerate(t *testing.T) {
+	handler := func(c echo.Context) error { return nil }
+	document := Generate(Info{Title: "Users API", Version: "1.0.0"}, controllers.RouteGroup{
+		Version:  "v1",
+		BasePath: "/users",
+		Routes: []controllers.Route{
+			{URI: "", Method: http.MethodPost, Function: handler, Summary: "Create user", Tags: []string{"users"},
+				Request: user{}, Response: user{}, ResponseStatus: http.StatusCreated, RequiresAuthentication: true},
+			{URI: "/:id", Method: http.MethodGet, Function: handler, Response: user{}},
+		},
+	})
+
+	create := document.Paths["/v1/users"]["post"]
+	assert.Equal(t, "Create user", create.Summary)
+	assert.Equal(t, "post_v1_users", create.OperationID)
+	assert.Equal(t, "#/components/schemas/"+testPackage+"user", create.RequestBody.Content[echo.MIMEApplicationJSON].Schema.Ref)
+	assert.Equal(t, "Created", create.Responses["201"].Description)
+	assert.Equal(t, "#/components/responses/NOT_AUTHORIZED", create.Responses["403"].Ref)
+	assert.Equal(t, "#/components/responses/UNSUPPORTED_MEDIA_TYPE", create.Responses["415"].Ref)
+	assert.Equal(t, []map[string][]string{{bearerSecurityScheme: {}}}, create.Security)
+
+	get := document.Paths["/v1/users/{id}"]["get"]
+	assert.Equal(t, []Parameter{{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}}, get.Parameters)
+	assert.Equal(t, "#/components/responses/RESOURCE_NOT_FOUND", get.Responses["404"].Ref)
+	assert.Nil(t, get.Responses["415"])
+
+	schema := document.Components.Schemas[testPackage+"user"]
+	assert.ElementsMatch(t, []string{"created_at", "id", "name"}, schema.Required)
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, schema.Properties["created_at"])
+	assert.Equal(t, "#/components/schemas/"+testPackage+"address", schema.Properties["address"].Ref)
+	assert.Equal(t, "array", schema.Properties["tags"].Type)
+	assert.Equal(t, "object", schema.Properties["metadata"].Type)
+	assert.NotContains(t, schema.Properties, "Password")
+
+	notFound := document.Components.Responses[string(faults.ResourceNotFound)]
+	issues := notFound.Content[echo.MIMEApplicationJSON].Schema.Properties["details"].Items.Properties["issue"].Enum
+	assert.Contains(t, issues, string(faults.InvalidResourceId))
+}
+
+func TestGenerateMarshalersAndComponentNames(t *testing.T) {
+	handler := func(c echo.Context) error { return nil }
+	document := Generate(Info{Title: "Pages API", Version: "1.0.0"}, controllers.RouteGroup{
+		Version:  "v1",
+		BasePath: "/pages",
+		Routes: []controllers.Route{
+			{URI: "/:id", Method: http.MethodGet, Function: handler, Response: Page{}},
+		},
+	})
+
+	schema := document.Components.Schemas[testPackage+"Page"]
+	assert.Equal(t, &Schema{Type: "string", Format: "uuid"}, schema.Properties["id"])
+	assert.Equal(t, &Schema{Type: "string"}, schema.Properties["amount"])
+	assert.Equal(t, "array", schema.Properties["checksum"].Type)
+	assert.Equal(t, "#/components/schemas/github.com.marcelofelixsalgado.financial-commons.api.responses.Page", schema.Properties["page"].Ref)
+	assert.Contains(t, document.Components.Schemas, "github.com.marcelofelixsalgado.financial-commons.api.responses.Page")
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.json")
+	document := Generate(Info{Title: "Users API", Version: "1.0.0"})
+	assert.Nil(t, document.WriteFile(path))
+
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	exported := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(content, &exported))
+	assert.Equal(t, Version, exported["openapi"])
+}
@@ -0,0 +1,134 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+	// Characters not allowed on the component keys
+	componentNameRegexp = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+)
+
+// schemaOf describes the type, adding the named structs to the components and referencing them
+func (generator *generator) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+	// Types encoding themselves (e.g. uuid.UUID, decimals) are documented as their text representation
+	if isMarshaler(t) {
+		if t.Kind() == reflect.Array && t.Len() == 16 && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "uuid"}
+		}
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		// Only the byte slices are encoded as base64, the byte arrays are encoded as arrays of numbers
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: generator.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: generator.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return generator.structSchema(t)
+		}
+		name := componentName(t)
+		if _, ok := generator.document.Components.Schemas[name]; !ok {
+			// Registered before describing the fields, so recursive types end up referencing themselves
+			generator.document.Components.Schemas[name] = &Schema{}
+			*generator.document.Components.Schemas[name] = *generator.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	// Interfaces and other dynamic values
+	return &Schema{}
+}
+
+func (generator *generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, options := jsonTag(field)
+		if name == "-" {
+			continue
+		}
+
+		// Embedded structs without a name have their fields promoted (even when the struct type is unexported)
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				promoted := generator.structSchema(embedded)
+				for property, propertySchema := range promoted.Properties {
+					schema.Properties[property] = propertySchema
+				}
+				schema.Required = append(schema.Required, promoted.Required...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = generator.schemaOf(field.Type)
+		if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Ptr {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return schema
+}
+
+// componentName keys the components by package and type name, so the types named alike on different packages do not
+// overwrite each other, e.g. github.com.marcelofelixsalgado.financial-commons.api.responses.Page
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	if t.PkgPath() != "" {
+		name = strings.ReplaceAll(t.PkgPath(), "/", ".") + "." + name
+	}
+	return componentNameRegexp.ReplaceAllString(name, "_")
+}
+
+func isMarshaler(t reflect.Type) bool {
+	pointer := reflect.PtrTo(t)
+	return t.Implements(jsonMarshalerType) || pointer.Implements(jsonMarshalerType) ||
+		t.Implements(textMarshalerType) || pointer.Implements(textMarshalerType)
+}
+
+func jsonTag(field reflect.StructField) (string, string) {
+	tag := field.Tag.Get("json")
+	name, options, _ := strings.Cut(tag, ",")
+	return name, options
+}
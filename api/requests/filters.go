@@ -2,28 +2,156 @@ package requests
 
 import (
 	"net/http"
+	"sort"
+	"strings"
 
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
 	"github.com/marcelofelixsalgado/financial-commons/pkg/infrastructure/repository/filter"
 )
 
+const listSeparator = ","
+
+// Query parameters reserved to paging and sorting, which are never filters
+var reservedParameters = map[string]bool{
+	"limit":  true,
+	"offset": true,
+	"cursor": true,
+	"sort":   true,
+}
+
+// Suffixes of the query parameter names selecting the criteria, e.g. amount_gte=10. Parameters without suffix are exact
+var criteriaSuffixes = []struct {
+	suffix   string
+	criteria filter.Criteria
+}{
+	{"_like", filter.Like},
+	{"_in", filter.List},
+	{"_gte", filter.GreaterOrEqual},
+	{"_gt", filter.Greater},
+	{"_lte", filter.LesserOrEqual},
+	{"_lt", filter.Lesser},
+}
+
+// ValidationError carries the response message describing the invalid query parameters
+type ValidationError struct {
+	ResponseMessage *responses.ResponseMessage
+}
+
+func (validationError *ValidationError) Error() string {
+	var issues []string
+	for _, detail := range validationError.ResponseMessage.Details {
+		issues = append(issues, detail.Issue+" ("+detail.Field+")")
+	}
+	return "invalid query parameters: " + strings.Join(issues, ", ")
+}
+
+// SetupFilters parses the query parameters into filters. The name suffix selects the criteria: name_like, status_in
+// (comma separated list), amount_gt, amount_gte, amount_lt and amount_lte. Repeated parameters are combined: the exact
+// and list ones into a single list, the others into one filter per value.
+//
+// Malformed parameters are reported all at once on a *ValidationError. The paging and sorting parameters are skipped
 func SetupFilters(r *http.Request) ([]filter.FilterParameter, error) {
+	return setupFilters(r, nil)
+}
+
+// setupFilters parses the filters, validating them against the schema when there is one
+func setupFilters(r *http.Request, schema *FilterSchema) ([]filter.FilterParameter, error) {
 
 	filterParameters := []filter.FilterParameter{}
+	responseMessage := responses.NewResponseMessageWithContext(r.Context())
 
 	queryParams := r.URL.Query()
-	for name, value := range queryParams {
-		filterParameter := filter.FilterParameter{
-			Name:  name,
-			Value: value[0],
+	names := make([]string, 0, len(queryParams))
+	for name := range queryParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, parameter := range names {
+		if reservedParameters[parameter] {
+			continue
+		}
+
+		values := queryParams[parameter]
+		name, criteria := parseCriteria(parameter)
+		if name == "" {
+			responseMessage.AddMessageByIssue(faults.InvalidParameter, responses.QueryParameter, parameter, "")
+			continue
+		}
+
+		if !validFilterValues(responseMessage, parameter, values, criteria) {
+			continue
+		}
+
+		if schema != nil {
+			field, ok := schema.field(name)
+			if !ok || !field.allows(criteria) {
+				responseMessage.AddMessageByIssue(faults.InvalidParameter, responses.QueryParameter, parameter, "")
+				continue
+			}
+			if !field.validValues(responseMessage, parameter, values, criteria) {
+				continue
+			}
+		}
+
+		switch {
+		case criteria == filter.List || (criteria == filter.Exact && len(values) > 1):
+			// Only the list parameters are split: the exact values may contain the separator
+			items := values
+			if criteria == filter.List {
+				items = nil
+				for _, value := range values {
+					items = append(items, strings.Split(value, listSeparator)...)
+				}
+			}
+			filterParameters = append(filterParameters, filter.FilterParameter{
+				Name:     name,
+				Value:    strings.Join(items, listSeparator),
+				Criteria: filter.List,
+				Values:   items,
+			})
+		default:
+			for _, value := range values {
+				filterParameters = append(filterParameters, filter.FilterParameter{
+					Name:     name,
+					Value:    value,
+					Criteria: criteria,
+				})
+			}
 		}
-		filterParameters = append(filterParameters, filterParameter)
 	}
 
+	if len(responseMessage.Details) > 0 {
+		return nil, &ValidationError{ResponseMessage: responseMessage}
+	}
 	return filterParameters, nil
 }
 
-// func getSuffix(fieldName string) string {
-// 	suffixes := map[repository.Criteria]string {
-// 		"_like", "_in", "_gt", "_gte", "_lt", "_lte"
-// 	}
-// }
+func parseCriteria(parameter string) (string, filter.Criteria) {
+	for _, criteriaSuffix := range criteriaSuffixes {
+		if strings.HasSuffix(parameter, criteriaSuffix.suffix) {
+			return strings.TrimSuffix(parameter, criteriaSuffix.suffix), criteriaSuffix.criteria
+		}
+	}
+	return parameter, filter.Exact
+}
+
+func validFilterValues(responseMessage *responses.ResponseMessage, parameter string, values []string, criteria filter.Criteria) bool {
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			responseMessage.AddMessageByIssue(faults.InvalidParameterValueBlank, responses.QueryParameter, parameter, "")
+			return false
+		}
+		if criteria != filter.List {
+			continue
+		}
+		for _, item := range strings.Split(value, listSeparator) {
+			if strings.TrimSpace(item) == "" {
+				responseMessage.AddMessageByIssue(faults.InvalidParameterFormat, responses.QueryParameter, parameter, value, "comma separated list of values")
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package requests
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/infrastructure/repository/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupFilters(t *testing.T) {
+	request := httptest.NewRequest("GET", "/v1/transactions?description_like=rent&status_in=open,paid&amount_gte=10&amount_lt=100&date_gt=2023-01-01&date_lte=2023-12-31&category=home&category=car&name=a,b", nil)
+
+	filterParameters, err := SetupFilters(request)
+	assert.Nil(t, err)
+	assert.Equal(t, []filter.FilterParameter{
+		{Name: "amount", Value: "10", Criteria: filter.GreaterOrEqual},
+		{Name: "amount", Value: "100", Criteria: filter.Lesser},
+		{Name: "category", Value: "home,car", Criteria: filter.List, Values: []string{"home", "car"}},
+		{Name: "date", Value: "2023-01-01", Criteria: filter.Greater},
+		{Name: "date", Value: "2023-12-31", Criteria: filter.LesserOrEqual},
+		{Name: "description", Value: "rent", Criteria: filter.Like},
+		{Name: "name", Value: "a,b", Criteria: filter.Exact},
+		{Name: "status", Value: "open,paid", Criteria: filter.List, Values: []string{"open", "paid"}},
+	}, filterParameters)
+}
+
+func TestSetupFiltersRepeatedList(t *testing.T) {
+	request := httptest.NewRequest("GET", "/v1/transactions?status_in=open&status_in=paid,late", nil)
+
+	filterParameters, err := SetupFilters(request)
+	assert.Nil(t, err)
+	assert.Equal(t, []filter.FilterParameter{
+		{Name: "status", Value: "open,paid,late", Criteria: filter.List, Values: []string{"open", "paid", "late"}},
+	}, filterParameters)
+}
+
+func TestSetupFiltersMalformed(t *testing.T) {
+	request := httptest.NewRequest("GET", "/v1/transactions?_gt=1&name=&status_in=open,,paid", nil)
+
+	filterParameters, err := SetupFilters(request)
+	assert.Nil(t, filterParameters)
+
+	var validationError *ValidationError
+	assert.True(t, errors.As(err, &validationError))
+
+	responseMessage := validationError.ResponseMessage
+	assert.Equal(t, 3, len(responseMessage.Details))
+	assert.Equal(t, responses.ResponseMessageDetail{
+		Issue:       string(faults.InvalidParameter),
+		Description: "Cannot be specified as part of the request",
+		Location:    responses.QueryParameter,
+		Field:       "_gt",
+	}, responseMessage.Details[0])
+	assert.Equal(t, string(faults.InvalidParameterValueBlank), responseMessage.Details[1].Issue)
+	assert.Equal(t, responses.ResponseMessageDetail{
+		Issue:       string(faults.InvalidParameterFormat),
+		Description: "Field value does not conform to the expected format: comma separated list of values",
+		Location:    responses.QueryParameter,
+		Field:       "status_in",
+		Value:       "open,,paid",
+	}, responseMessage.Details[2])
+}
+
+var transactionFilterSchema = NewFilterSchema(
+	FilterField{Name: "description", Type: StringField},
+	FilterField{Name: "amount", Type: DecimalField},
+	FilterField{Name: "installments", Type: IntegerField, Digits: 2},
+	FilterField{Name: "date", Type: DateField, Column: "transaction_date"},
+	FilterField{Name: "created_at", Type: DateTimeField},
+	FilterField{Name: "paid", Type: BooleanField},
+	FilterField{Name: "category_id", Type: UUIDField},
+	FilterField{Name: "status", Type: EnumField, Values: []string{"open", "paid"}, Criteria: []filter.Criteria{filter.Exact}},
+)
+
+func TestFilterSchemaParse(t *testing.T) {
+	request := httptest.NewRequest("GET", "/v1/transactions?description_like=rent&amount_gte=10.50&installments=12&date_lt=2023-12-31&created_at_gt=2023-01-01T10:00:00Z&paid=true&category_id_in=6ba7b810-9dad-11d1-80b4-00c04fd430c8&status=open&limit=10&sort=-date", nil)
+
+	filterParameters, err := transactionFilterSchema.Parse(request)
+	assert.Nil(t, err)
+	assert.Equal(t, []filter.FilterParameter{
+		{Name: "amount", Value: "10.50", Criteria: filter.GreaterOrEqual},
+		{Name: "category_id", Value: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", Criteria: filter.List, Values: []string{"6ba7b810-9dad-11d1-80b4-00c04fd430c8"}},
+		{Name: "created_at", Value: "2023-01-01T10:00:00Z", Criteria: filter.Greater},
+		{Name: "date", Value: "2023-12-31", Criteria: filter.Lesser},
+		{Name: "description", Value: "rent", Criteria: filter.Like},
+		{Name: "installments", Value: "12", Criteria: filter.Exact},
+		{Name: "paid", Value: "true", Criteria: filter.Exact},
+		{Name: "status", Value: "open", Criteria: filter.Exact},
+	}, filterParameters)
+}
+
+func TestFilterSchemaColumns(t *testing.T) {
+	columns := transactionFilterSchema.Columns()
+	assert.Equal(t, "transaction_date", columns["date"])
+	assert.Equal(t, "amount", columns["amount"])
+	assert.Len(t, columns, len(transactionFilterSchema.Fields))
+}
+
+func TestFilterSchemaParseInvalid(t *testing.T) {
+	tests := []struct {
+		query       string
+		issue       faults.Issue
+		description string
+	}{
+		{"unknown=1", faults.InvalidParameter, "Cannot be specified as part of the request"},
+		{"status_in=open", faults.InvalidParameter, "Cannot be specified as part of the request"},
+		{"paid_gt=true", faults.InvalidParameter, "Cannot be specified as part of the request"},
+		{"amount=10.505", faults.InvalidDecimalValue, "Field value is invalid. It should have a value with maximum 15 digits and 2 fractions"},
+		{"installments=100", faults.InvalidIntegerValue, "Field value should have a value with maximum 2 digits"},
+		{"installments_in=1,a", faults.InvalidIntegerValue, "Field value should have a value with maximum 2 digits"},
+		{"date=2023-13-01", faults.InvalidDateValue, "Field value is invalid. Expected format: YYYY-MM-DD"},
+		{"created_at=2023-01-01", faults.InvalidDateTimeValue, "Field value is invalid. Expected format: YYYY-MM-DDThh:mm:ssZ"},
+		{"paid=yes", faults.InvalidBooleanValue, "Field value is invalid. Expected values: true or false"},
+		{"category_id=123", faults.InvalidUUIDValue, "Invalid UUID string format"},
+		{"status=late", faults.InvalidParameterValue, "Field value is invalid"},
+	}
+
+	for _, test := range tests {
+		_, err := transactionFilterSchema.Parse(httptest.NewRequest("GET", "/v1/transactions?"+test.query, nil))
+
+		var validationError *ValidationError
+		assert.True(t, errors.As(err, &validationError), test.query)
+		detail := validationError.ResponseMessage.Details[0]
+		assert.Equal(t, string(test.issue), detail.Issue, test.query)
+		assert.Equal(t, test.description, detail.Description, test.query)
+		assert.Equal(t, responses.QueryParameter, detail.Location, test.query)
+	}
+}
@@ -0,0 +1,304 @@
+package requests
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/infrastructure/repository"
+	"github.com/marcelofelixsalgado/financial-commons/settings"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	LimitParameter  = "limit"
+	OffsetParameter = "offset"
+	CursorParameter = "cursor"
+
+	cursorSignatureSeparator = "."
+	cursorKeyLabel           = "financial-commons pagination cursor"
+	descendingPrefix         = "-"
+)
+
+var (
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrMissingCursorKey = errors.New("the key signing the cursors is required: set SECRET_KEY")
+)
+
+// PaginationConfig holds the page sizes and the key signing the cursors
+type PaginationConfig struct {
+	DefaultLimit int
+	MaxLimit     int
+	SecretKey    []byte
+}
+
+// NewPaginationConfig builds the pagination from the settings. The cursors are signed with a key derived from the
+// token key (see CursorKey), so a cursor signature is never valid as a token signature. The token key is required,
+// since the key derived from an empty one could be computed by anyone
+func NewPaginationConfig(config settings.ConfigType) (PaginationConfig, error) {
+	if len(config.SecretKey) == 0 {
+		return PaginationConfig{}, ErrMissingCursorKey
+	}
+	return PaginationConfig{
+		DefaultLimit: config.PageDefaultLimit,
+		MaxLimit:     config.PageMaxLimit,
+		SecretKey:    CursorKey(config.SecretKey),
+	}, nil
+}
+
+// CursorKey derives the key signing the cursors from the secret key (HKDF-SHA256 bound to the cursor label)
+func CursorKey(secretKey []byte) []byte {
+	key := make([]byte, sha256.Size)
+	// The reader only fails past 255 hashes of output
+	io.ReadFull(hkdf.New(sha256.New, secretKey, nil, []byte(cursorKeyLabel)), key)
+	return key
+}
+
+// Cursor is the position of a keyset page: the values of the sort fields of the row the page starts after (or
+// before, when Backward). Path and Sort bind the cursor to the endpoint and to the sort of the query, e.g. [-date id]
+type Cursor struct {
+	Path     string   `json:"p"`
+	Sort     []string `json:"s"`
+	Values   []string `json:"v"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+// Pagination is the page requested by the client, either by offset or by cursor
+type Pagination struct {
+	Limit  int
+	Offset int
+	Cursor *Cursor
+
+	config      PaginationConfig
+	cursorToken string
+	ctx         context.Context
+}
+
+// KeyFunc returns the values of the sort fields of an item (query.SortSpecs, the tiebreakers included), which position
+// the cursors of the page
+type KeyFunc func(item interface{}) []string
+
+// ParsePagination parses the limit, offset and cursor query parameters. The default limit is used when there is no
+// limit. The offset and the cursor cannot be combined. Invalid values are reported all at once on a *ValidationError
+func ParsePagination(r *http.Request, config PaginationConfig) (Pagination, error) {
+	pagination := Pagination{Limit: config.DefaultLimit, config: config, ctx: r.Context()}
+	responseMessage := responses.NewResponseMessageWithContext(r.Context())
+	queryParams := r.URL.Query()
+
+	if values, ok := queryParams[LimitParameter]; ok {
+		if limit, valid := parseInteger(responseMessage, LimitParameter, values[0]); valid {
+			switch {
+			case limit < 1:
+				responseMessage.AddMessageByIssue(faults.CannotBeZeroOrNegative, responses.QueryParameter, LimitParameter, values[0])
+			case config.MaxLimit > 0 && limit > config.MaxLimit:
+				responseMessage.AddMessageByIssue(faults.FieldValueTooHigh, responses.QueryParameter, LimitParameter, values[0], strconv.Itoa(config.MaxLimit))
+			default:
+				pagination.Limit = limit
+			}
+		}
+	}
+
+	if values, ok := queryParams[OffsetParameter]; ok {
+		if offset, valid := parseInteger(responseMessage, OffsetParameter, values[0]); valid {
+			if offset < 0 {
+				responseMessage.AddMessageByIssue(faults.CannotBeNegative, responses.QueryParameter, OffsetParameter, values[0])
+			} else {
+				pagination.Offset = offset
+			}
+		}
+	}
+
+	if values, ok := queryParams[CursorParameter]; ok {
+		switch {
+		case strings.TrimSpace(values[0]) == "":
+			responseMessage.AddMessageByIssue(faults.InvalidParameterValueBlank, responses.QueryParameter, CursorParameter, "")
+		case queryParams.Has(OffsetParameter):
+			responseMessage.AddMessageByIssue(faults.InvalidParameter, responses.QueryParameter, OffsetParameter, "")
+		default:
+			cursor, err := DecodeCursor(values[0], config.SecretKey)
+			if err != nil || cursor.Path != r.URL.Path {
+				responseMessage.AddMessageByIssue(faults.InvalidCursor, responses.QueryParameter, CursorParameter, values[0])
+			} else {
+				pagination.Cursor = &cursor
+				pagination.cursorToken = values[0]
+			}
+		}
+	}
+
+	if len(responseMessage.Details) > 0 {
+		return Pagination{}, &ValidationError{ResponseMessage: responseMessage}
+	}
+	return pagination, nil
+}
+
+// Apply pages the query. One row more than the limit is read, so NewPage knows whether there is a next page. The
+// cursor must have been issued for the sort of the query
+func (pagination Pagination) Apply(query repository.Query) (repository.Query, error) {
+	query.Limit = pagination.Limit + 1
+	query.Offset = pagination.Offset
+	query.Keyset = nil
+
+	if pagination.Cursor != nil {
+		sort := cursorSort(query.SortSpecs())
+		if len(sort) == 0 || strings.Join(pagination.Cursor.Sort, listSeparator) != strings.Join(sort, listSeparator) || len(pagination.Cursor.Values) != len(sort) {
+			responseMessage := responses.NewResponseMessageWithContext(pagination.ctx).AddMessageByIssue(faults.InvalidCursor, responses.QueryParameter, CursorParameter, pagination.cursorToken)
+			return repository.Query{}, &ValidationError{ResponseMessage: responseMessage}
+		}
+		values := make([]interface{}, 0, len(pagination.Cursor.Values))
+		for _, value := range pagination.Cursor.Values {
+			values = append(values, value)
+		}
+		query.Keyset = &repository.Keyset{Values: values, Backward: pagination.Cursor.Backward}
+	}
+	return query, nil
+}
+
+// NewPage builds the envelope of the rows read by the query paged by Apply (a slice). The extra row is dropped and
+// the backward pages are put back in the sort order. The links page by cursor when there is a key function, and by
+// offset when there is none or the client paged by offset. The links keep the other query parameters of the request
+func (pagination Pagination) NewPage(r *http.Request, query repository.Query, rows interface{}, key KeyFunc) responses.Page {
+	items := reflect.ValueOf(rows)
+	hasMore := items.Len() > pagination.Limit
+	if hasMore {
+		items = items.Slice(0, pagination.Limit)
+	}
+	backward := pagination.Cursor != nil && pagination.Cursor.Backward
+	if backward {
+		reversed := reflect.MakeSlice(items.Type(), items.Len(), items.Len())
+		reflect.Copy(reversed, items)
+		swap := reflect.Swapper(reversed.Interface())
+		for i, j := 0, reversed.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+		items = reversed
+	}
+
+	page := responses.Page{Items: items.Interface()}
+
+	if key == nil || pagination.Offset > 0 || len(pagination.config.SecretKey) == 0 {
+		if hasMore {
+			page.Links.Next = pagination.link(r, OffsetParameter, strconv.Itoa(pagination.Offset+pagination.Limit))
+		}
+		if pagination.Offset > 0 {
+			previousOffset := pagination.Offset - pagination.Limit
+			if previousOffset < 0 {
+				previousOffset = 0
+			}
+			page.Links.Prev = pagination.link(r, OffsetParameter, strconv.Itoa(previousOffset))
+		}
+		return page
+	}
+
+	if items.Len() == 0 {
+		return page
+	}
+	sort := cursorSort(query.SortSpecs())
+	// The backward pages were reached from a later page and the forward ones with a cursor from an earlier page
+	if hasMore || backward {
+		next := Cursor{Path: r.URL.Path, Sort: sort, Values: key(items.Index(items.Len() - 1).Interface())}
+		page.Links.Next = pagination.link(r, CursorParameter, pagination.encode(next))
+	}
+	if hasMore && backward || pagination.Cursor != nil && !backward {
+		prev := Cursor{Path: r.URL.Path, Sort: sort, Values: key(items.Index(0).Interface()), Backward: true}
+		page.Links.Prev = pagination.link(r, CursorParameter, pagination.encode(prev))
+	}
+	return page
+}
+
+// EncodeCursor returns the opaque representation of the cursor: its JSON and HMAC-SHA256 signature, base64 encoded
+func EncodeCursor(cursor Cursor, key []byte) (string, error) {
+	if len(key) == 0 {
+		return "", ErrMissingCursorKey
+	}
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + cursorSignatureSeparator + base64.RawURLEncoding.EncodeToString(sign(payload, key)), nil
+}
+
+// DecodeCursor verifies the signature of the cursor and decodes it
+func DecodeCursor(token string, key []byte) (Cursor, error) {
+	if len(key) == 0 {
+		return Cursor{}, ErrMissingCursorKey
+	}
+	encodedPayload, encodedSignature, ok := strings.Cut(token, cursorSignatureSeparator)
+	if !ok {
+		return Cursor{}, ErrInvalidCursor
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, sign(payload, key)) {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+func (pagination Pagination) encode(cursor Cursor) string {
+	// The cursor holds only strings and NewPage checked the key, so the encoding cannot fail
+	token, _ := EncodeCursor(cursor, pagination.config.SecretKey)
+	return token
+}
+
+// link returns the URI of the request with the paging parameter replaced
+func (pagination Pagination) link(r *http.Request, parameter string, value string) string {
+	queryParams := r.URL.Query()
+	queryParams.Del(OffsetParameter)
+	queryParams.Del(CursorParameter)
+	queryParams.Set(LimitParameter, strconv.Itoa(pagination.Limit))
+	queryParams.Set(parameter, value)
+
+	link := url.URL{Path: r.URL.Path, RawQuery: queryParams.Encode()}
+	return link.String()
+}
+
+func parseInteger(responseMessage *responses.ResponseMessage, parameter string, value string) (int, bool) {
+	if strings.TrimSpace(value) == "" {
+		responseMessage.AddMessageByIssue(faults.InvalidParameterValueBlank, responses.QueryParameter, parameter, "")
+		return 0, false
+	}
+	integer, err := strconv.Atoi(value)
+	if err != nil {
+		responseMessage.AddMessageByIssue(faults.InvalidParameterValue, responses.QueryParameter, parameter, value)
+		return 0, false
+	}
+	return integer, true
+}
+
+// cursorSort returns the sort bound to the cursors, the descending fields prefixed by -
+func cursorSort(sortSpecs []repository.SortSpec) []string {
+	sort := make([]string, 0, len(sortSpecs))
+	for _, sortSpec := range sortSpecs {
+		if sortSpec.Direction == repository.Descending {
+			sort = append(sort, descendingPrefix+sortSpec.Field)
+		} else {
+			sort = append(sort, sortSpec.Field)
+		}
+	}
+	return sort
+}
+
+func sign(payload []byte, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
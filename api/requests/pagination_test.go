@@ -0,0 +1,191 @@
+package requests
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/infrastructure/repository"
+	"github.com/marcelofelixsalgado/financial-commons/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+var testPaginationConfig = PaginationConfig{DefaultLimit: 2, MaxLimit: 10, SecretKey: []byte("secret")}
+
+type transaction struct {
+	Id   string
+	Date string
+}
+
+func transactionKey(item interface{}) []string {
+	return []string{item.(transaction).Date, item.(transaction).Id}
+}
+
+var transactionsQuery = repository.Query{
+	Table:       "transactions",
+	Columns:     repository.Columns{"id": "id", "date": "transaction_date"},
+	Sort:        []repository.SortSpec{{Field: "date", Direction: repository.Descending}},
+	Tiebreakers: []string{"id"},
+}
+
+func TestParsePaginationOffset(t *testing.T) {
+	request := httptest.NewRequest("GET", "/v1/transactions?status=open&limit=2&offset=4", nil)
+
+	pagination, err := ParsePagination(request, testPaginationConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, pagination.Limit)
+	assert.Equal(t, 4, pagination.Offset)
+
+	query, err := pagination.Apply(transactionsQuery)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, query.Limit)
+	assert.Equal(t, 4, query.Offset)
+
+	rows := []transaction{{"5", "2023-01-05"}, {"6", "2023-01-06"}, {"7", "2023-01-07"}}
+	page := pagination.NewPage(request, query, rows, transactionKey)
+	assert.Equal(t, rows[:2], page.Items)
+	assert.Equal(t, "/v1/transactions?limit=2&offset=6&status=open", page.Links.Next)
+	assert.Equal(t, "/v1/transactions?limit=2&offset=2&status=open", page.Links.Prev)
+
+	// Last page
+	page = pagination.NewPage(request, query, rows[:1], transactionKey)
+	assert.Equal(t, "", page.Links.Next)
+	assert.Equal(t, "/v1/transactions?limit=2&offset=2&status=open", page.Links.Prev)
+}
+
+func TestParsePaginationDefaults(t *testing.T) {
+	request := httptest.NewRequest("GET", "/v1/transactions", nil)
+
+	pagination, err := ParsePagination(request, testPaginationConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, pagination.Limit)
+	assert.Equal(t, 0, pagination.Offset)
+	assert.Nil(t, pagination.Cursor)
+
+	// Paged by offset when there is no key function
+	page := pagination.NewPage(request, transactionsQuery, []transaction{{"1", "a"}, {"2", "b"}, {"3", "c"}}, nil)
+	assert.Equal(t, "/v1/transactions?limit=2&offset=2", page.Links.Next)
+	assert.Equal(t, "", page.Links.Prev)
+}
+
+func TestPaginationCursor(t *testing.T) {
+	request := httptest.NewRequest("GET", "/v1/transactions?status=open", nil)
+	pagination, err := ParsePagination(request, testPaginationConfig)
+	assert.Nil(t, err)
+
+	query, err := pagination.Apply(transactionsQuery)
+	assert.Nil(t, err)
+	assert.Nil(t, query.Keyset)
+
+	// First page
+	page := pagination.NewPage(request, query, []transaction{{"9", "2023-01-09"}, {"8", "2023-01-08"}, {"7", "2023-01-07"}}, transactionKey)
+	assert.Equal(t, []transaction{{"9", "2023-01-09"}, {"8", "2023-01-08"}}, page.Items)
+	assert.Equal(t, "", page.Links.Prev)
+	next := cursorOf(t, page.Links.Next)
+	assert.Equal(t, Cursor{Path: "/v1/transactions", Sort: []string{"-date", "id"}, Values: []string{"2023-01-08", "8"}}, next)
+
+	// Second page
+	request = httptest.NewRequest("GET", page.Links.Next, nil)
+	pagination, err = ParsePagination(request, testPaginationConfig)
+	assert.Nil(t, err)
+	query, err = pagination.Apply(transactionsQuery)
+	assert.Nil(t, err)
+	assert.Equal(t, &repository.Keyset{Values: []interface{}{"2023-01-08", "8"}}, query.Keyset)
+
+	page = pagination.NewPage(request, query, []transaction{{"7", "2023-01-07"}, {"6", "2023-01-06"}}, transactionKey)
+	assert.Equal(t, "", page.Links.Next)
+	prev := cursorOf(t, page.Links.Prev)
+	assert.Equal(t, Cursor{Path: "/v1/transactions", Sort: []string{"-date", "id"}, Values: []string{"2023-01-07", "7"}, Backward: true}, prev)
+
+	// Back to the first page: the rows are read in the reverse order
+	request = httptest.NewRequest("GET", page.Links.Prev, nil)
+	pagination, err = ParsePagination(request, testPaginationConfig)
+	assert.Nil(t, err)
+	query, err = pagination.Apply(transactionsQuery)
+	assert.Nil(t, err)
+	assert.True(t, query.Keyset.Backward)
+
+	page = pagination.NewPage(request, query, []transaction{{"8", "2023-01-08"}, {"9", "2023-01-09"}}, transactionKey)
+	assert.Equal(t, []transaction{{"9", "2023-01-09"}, {"8", "2023-01-08"}}, page.Items)
+	assert.Equal(t, "", page.Links.Prev)
+	assert.Equal(t, Cursor{Path: "/v1/transactions", Sort: []string{"-date", "id"}, Values: []string{"2023-01-08", "8"}}, cursorOf(t, page.Links.Next))
+}
+
+func TestPaginationCursorSortMismatch(t *testing.T) {
+	token, err := EncodeCursor(Cursor{Path: "/v1/transactions", Sort: []string{"date"}, Values: []string{"2023-01-08"}}, testPaginationConfig.SecretKey)
+	assert.Nil(t, err)
+
+	pagination, err := ParsePagination(httptest.NewRequest("GET", "/v1/transactions?cursor="+token, nil), testPaginationConfig)
+	assert.Nil(t, err)
+
+	_, err = pagination.Apply(transactionsQuery)
+	var validationError *ValidationError
+	assert.True(t, errors.As(err, &validationError))
+	assert.Equal(t, string(faults.InvalidCursor), validationError.ResponseMessage.Details[0].Issue)
+	assert.Equal(t, token, validationError.ResponseMessage.Details[0].Value)
+}
+
+func TestNewPaginationConfigCursorKey(t *testing.T) {
+	config, err := NewPaginationConfig(settings.ConfigType{PageDefaultLimit: 20, PageMaxLimit: 100, SecretKey: []byte("secret")})
+	assert.Nil(t, err)
+	assert.Equal(t, CursorKey([]byte("secret")), config.SecretKey)
+	assert.Len(t, config.SecretKey, 32)
+	assert.NotEqual(t, CursorKey([]byte("another secret")), config.SecretKey)
+
+	// The cursors signed with the token key are rejected
+	token, err := EncodeCursor(Cursor{Sort: []string{"id"}, Values: []string{"1"}}, []byte("secret"))
+	assert.Nil(t, err)
+	_, err = DecodeCursor(token, config.SecretKey)
+	assert.True(t, errors.Is(err, ErrInvalidCursor))
+
+	// The key derived from an empty secret key could be computed by anyone
+	_, err = NewPaginationConfig(settings.ConfigType{})
+	assert.True(t, errors.Is(err, ErrMissingCursorKey))
+	_, err = EncodeCursor(Cursor{Sort: []string{"id"}, Values: []string{"1"}}, nil)
+	assert.True(t, errors.Is(err, ErrMissingCursorKey))
+}
+
+func TestParsePaginationInvalid(t *testing.T) {
+	token, err := EncodeCursor(Cursor{Path: "/v1/transactions", Sort: []string{"id"}, Values: []string{"1"}}, []byte("another key"))
+	assert.Nil(t, err)
+	// Issued by another endpoint
+	otherEndpointToken, err := EncodeCursor(Cursor{Path: "/v1/users", Sort: []string{"id"}, Values: []string{"1"}}, testPaginationConfig.SecretKey)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		query  string
+		issues []faults.Issue
+	}{
+		{"limit=abc&offset=", []faults.Issue{faults.InvalidParameterValue, faults.InvalidParameterValueBlank}},
+		{"limit=0&offset=-1", []faults.Issue{faults.CannotBeZeroOrNegative, faults.CannotBeNegative}},
+		{"limit=11", []faults.Issue{faults.FieldValueTooHigh}},
+		{"cursor=" + token, []faults.Issue{faults.InvalidCursor}},
+		{"cursor=abc", []faults.Issue{faults.InvalidCursor}},
+		{"cursor=" + otherEndpointToken, []faults.Issue{faults.InvalidCursor}},
+		{"cursor=" + token + "&offset=2", []faults.Issue{faults.InvalidParameter}},
+	}
+
+	for _, test := range tests {
+		_, err := ParsePagination(httptest.NewRequest("GET", "/v1/transactions?"+test.query, nil), testPaginationConfig)
+
+		var validationError *ValidationError
+		assert.True(t, errors.As(err, &validationError), test.query)
+
+		var issues []faults.Issue
+		for _, detail := range validationError.ResponseMessage.Details {
+			issues = append(issues, faults.Issue(detail.Issue))
+		}
+		assert.Equal(t, test.issues, issues, test.query)
+	}
+}
+
+func cursorOf(t *testing.T, link string) Cursor {
+	linkURL, err := url.Parse(link)
+	assert.Nil(t, err)
+
+	cursor, err := DecodeCursor(linkURL.Query().Get(CursorParameter), testPaginationConfig.SecretKey)
+	assert.Nil(t, err)
+	return cursor
+}
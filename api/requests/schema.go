@@ -0,0 +1,199 @@
+package requests
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/infrastructure/repository"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/infrastructure/repository/filter"
+	uuid "github.com/satori/go.uuid"
+)
+
+type FieldType string
+
+const (
+	StringField   FieldType = "string"
+	IntegerField  FieldType = "integer"
+	DecimalField  FieldType = "decimal"
+	DateField     FieldType = "date"
+	DateTimeField FieldType = "datetime"
+	BooleanField  FieldType = "boolean"
+	UUIDField     FieldType = "uuid"
+	EnumField     FieldType = "enum"
+)
+
+const (
+	DateLayout     = "2006-01-02"
+	DateTimeLayout = time.RFC3339
+
+	defaultIntegerDigits = 19
+	defaultDecimalDigits = 15
+	defaultDecimalScale  = 2
+)
+
+var decimalRegexp = regexp.MustCompile(`^-?(\d+)(?:\.(\d+))?$`)
+
+var comparisonCriteria = []filter.Criteria{filter.Exact, filter.List, filter.Greater, filter.GreaterOrEqual, filter.Lesser, filter.LesserOrEqual}
+
+// Criteria allowed by type when the field does not declare its own
+var defaultCriteria = map[FieldType][]filter.Criteria{
+	StringField:   {filter.Exact, filter.Like, filter.List},
+	IntegerField:  comparisonCriteria,
+	DecimalField:  comparisonCriteria,
+	DateField:     comparisonCriteria,
+	DateTimeField: comparisonCriteria,
+	BooleanField:  {filter.Exact},
+	UUIDField:     {filter.Exact, filter.List},
+	EnumField:     {filter.Exact, filter.List},
+}
+
+// FilterField declares a field which can be filtered by the clients
+type FilterField struct {
+	// Name of the query parameter (without the criteria suffix)
+	Name string
+	// Column of the database table. Same as the name when empty
+	Column string
+	Type   FieldType
+	// Allowed criteria. The defaults of the type when empty
+	Criteria []filter.Criteria
+	// Allowed values of the enum fields
+	Values []string
+	// Maximum number of digits (integer and decimal fields) and of fraction digits (decimal fields)
+	Digits    int
+	Fractions int
+}
+
+// FilterSchema is the whitelist of the fields an endpoint can be filtered by
+type FilterSchema struct {
+	Fields []FilterField
+}
+
+func NewFilterSchema(fields ...FilterField) FilterSchema {
+	return FilterSchema{Fields: fields}
+}
+
+// Parse parses the query parameters as SetupFilters does, rejecting the fields out of the schema, the criteria not
+// allowed and the values not matching the type. The filters keep the field names: Columns maps them to the columns
+func (schema FilterSchema) Parse(r *http.Request) ([]filter.FilterParameter, error) {
+	return setupFilters(r, &schema)
+}
+
+// Columns returns the whitelist mapping the field names to the columns, for repository.Query.Columns
+func (schema FilterSchema) Columns() repository.Columns {
+	columns := make(repository.Columns, len(schema.Fields))
+	for _, field := range schema.Fields {
+		columns[field.Name] = field.column()
+	}
+	return columns
+}
+
+func (schema FilterSchema) field(name string) (FilterField, bool) {
+	for _, field := range schema.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return FilterField{}, false
+}
+
+func (field FilterField) column() string {
+	if field.Column != "" {
+		return field.Column
+	}
+	return field.Name
+}
+
+func (field FilterField) allows(criteria filter.Criteria) bool {
+	allowed := field.Criteria
+	if len(allowed) == 0 {
+		allowed = defaultCriteria[field.Type]
+	}
+	for _, allowedCriteria := range allowed {
+		if allowedCriteria == criteria {
+			return true
+		}
+	}
+	return false
+}
+
+// validValues validates each value (and each item of the lists) against the field type
+func (field FilterField) validValues(responseMessage *responses.ResponseMessage, parameter string, values []string, criteria filter.Criteria) bool {
+	for _, value := range values {
+		items := []string{value}
+		if criteria == filter.List {
+			items = strings.Split(value, listSeparator)
+		}
+		for _, item := range items {
+			if !field.validValue(responseMessage, parameter, item, criteria) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (field FilterField) validValue(responseMessage *responses.ResponseMessage, parameter string, value string, criteria filter.Criteria) bool {
+	// Like matches parts of the value, which do not need to be valid values by themselves
+	if criteria == filter.Like {
+		return true
+	}
+
+	switch field.Type {
+	case IntegerField:
+		digits := field.Digits
+		if digits == 0 {
+			digits = defaultIntegerDigits
+		}
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil || len(strings.TrimPrefix(value, "-")) > digits {
+			responseMessage.AddMessageByIssue(faults.InvalidIntegerValue, responses.QueryParameter, parameter, value, strconv.Itoa(digits))
+			return false
+		}
+	case DecimalField:
+		digits, fractions := field.Digits, field.Fractions
+		if digits == 0 {
+			digits = defaultDecimalDigits
+		}
+		if fractions == 0 {
+			fractions = defaultDecimalScale
+		}
+		matches := decimalRegexp.FindStringSubmatch(value)
+		if matches == nil || len(matches[1])+len(matches[2]) > digits || len(matches[2]) > fractions {
+			responseMessage.AddMessageByIssue(faults.InvalidDecimalValue, responses.QueryParameter, parameter, value, strconv.Itoa(digits), strconv.Itoa(fractions))
+			return false
+		}
+	case DateField:
+		if _, err := time.Parse(DateLayout, value); err != nil {
+			responseMessage.AddMessageByIssue(faults.InvalidDateValue, responses.QueryParameter, parameter, value)
+			return false
+		}
+	case DateTimeField:
+		if _, err := time.Parse(DateTimeLayout, value); err != nil {
+			responseMessage.AddMessageByIssue(faults.InvalidDateTimeValue, responses.QueryParameter, parameter, value)
+			return false
+		}
+	case BooleanField:
+		if value != "true" && value != "false" {
+			responseMessage.AddMessageByIssue(faults.InvalidBooleanValue, responses.QueryParameter, parameter, value)
+			return false
+		}
+	case UUIDField:
+		if _, err := uuid.FromString(value); err != nil {
+			responseMessage.AddMessageByIssue(faults.InvalidUUIDValue, responses.QueryParameter, parameter, value)
+			return false
+		}
+	case EnumField:
+		for _, allowed := range field.Values {
+			if value == allowed {
+				return true
+			}
+		}
+		responseMessage.AddMessageByIssue(faults.InvalidParameterValue, responses.QueryParameter, parameter, value)
+		return false
+	}
+	return true
+}
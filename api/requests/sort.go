@@ -0,0 +1,112 @@
+package requests
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/infrastructure/repository"
+)
+
+const SortParameter = "sort"
+
+// SortField declares a field which can be sorted by the clients
+type SortField struct {
+	// Name on the sort parameter
+	Name string
+	// Column of the database table. Same as the name when empty
+	Column string
+}
+
+// SortSchema is the whitelist of the fields an endpoint can be sorted by
+type SortSchema struct {
+	Fields []SortField
+	// Sort used when the client does not send one, by field name (optional)
+	Default []repository.SortSpec
+}
+
+func NewSortSchema(fields ...SortField) SortSchema {
+	return SortSchema{Fields: fields}
+}
+
+// SetupSort parses the sort parameter of the fields named after the columns, e.g. sort=-date,amount (see
+// SortSchema.Parse)
+func SetupSort(r *http.Request, fields ...string) ([]repository.SortSpec, error) {
+	schema := SortSchema{}
+	for _, field := range fields {
+		schema.Fields = append(schema.Fields, SortField{Name: field})
+	}
+	return schema.Parse(r)
+}
+
+// Parse parses the sort parameter: a comma separated list of fields, the descending ones prefixed by -, e.g.
+// sort=-date,amount. The sort keeps the field names: Columns maps them to the columns. The fields out of the schema, the repeated ones and the
+// empty items are reported all at once on a *ValidationError. The default sort is returned when there is no sort
+func (schema SortSchema) Parse(r *http.Request) ([]repository.SortSpec, error) {
+	values, ok := r.URL.Query()[SortParameter]
+	if !ok {
+		return schema.Default, nil
+	}
+
+	value := strings.Join(values, listSeparator)
+	responseMessage := responses.NewResponseMessageWithContext(r.Context())
+	if strings.TrimSpace(value) == "" {
+		responseMessage.AddMessageByIssue(faults.InvalidParameterValueBlank, responses.QueryParameter, SortParameter, "")
+		return nil, &ValidationError{ResponseMessage: responseMessage}
+	}
+
+	sortSpecs := []repository.SortSpec{}
+	sorted := map[string]bool{}
+	for _, item := range strings.Split(value, listSeparator) {
+		name := strings.TrimSpace(item)
+		direction := repository.Ascending
+		if strings.HasPrefix(name, descendingPrefix) {
+			name = strings.TrimPrefix(name, descendingPrefix)
+			direction = repository.Descending
+		}
+
+		field, ok := schema.field(name)
+		if !ok || sorted[name] {
+			// The empty items are reported with the whole parameter, since they have no value of their own
+			invalidValue := item
+			if strings.TrimSpace(item) == "" {
+				invalidValue = value
+			}
+			responseMessage.AddMessageByIssue(faults.InvalidParameterValue, responses.QueryParameter, SortParameter, invalidValue)
+			continue
+		}
+		sorted[name] = true
+		sortSpecs = append(sortSpecs, repository.SortSpec{Field: field.Name, Direction: direction})
+	}
+
+	if len(responseMessage.Details) > 0 {
+		return nil, &ValidationError{ResponseMessage: responseMessage}
+	}
+	return sortSpecs, nil
+}
+
+// Columns returns the whitelist mapping the field names to the columns, for repository.Query.Columns
+func (schema SortSchema) Columns() repository.Columns {
+	columns := make(repository.Columns, len(schema.Fields))
+	for _, field := range schema.Fields {
+		columns[field.Name] = field.column()
+	}
+	return columns
+}
+
+func (schema SortSchema) field(name string) (SortField, bool) {
+	for _, field := range schema.Fields {
+		if field.Name == name {
+			return field, true
+		}
+	}
+	return SortField{}, false
+}
+
+func (field SortField) column() string {
+	if field.Column != "" {
+		return field.Column
+	}
+	return field.Name
+}
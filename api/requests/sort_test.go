@@ -0,0 +1,95 @@
+package requests
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/marcelofelixsalgado/financial-commons/api/responses"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/infrastructure/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+var transactionSortSchema = SortSchema{
+	Fields: []SortField{
+		{Name: "date", Column: "transaction_date"},
+		{Name: "amount"},
+	},
+	Default: []repository.SortSpec{{Field: "date", Direction: repository.Descending}},
+}
+
+func TestSortSchemaParse(t *testing.T) {
+	request := httptest.NewRequest("GET", "/v1/transactions?sort=-date,amount", nil)
+
+	sortSpecs, err := transactionSortSchema.Parse(request)
+	assert.Nil(t, err)
+	assert.Equal(t, []repository.SortSpec{
+		{Field: "date", Direction: repository.Descending},
+		{Field: "amount", Direction: repository.Ascending},
+	}, sortSpecs)
+
+	sortSpecs, err = transactionSortSchema.Parse(httptest.NewRequest("GET", "/v1/transactions", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, transactionSortSchema.Default, sortSpecs)
+}
+
+func TestSchemasQueryBuild(t *testing.T) {
+	request := httptest.NewRequest("GET", "/v1/transactions?date_gte=2023-01-01&amount_lt=100&sort=-date", nil)
+
+	filterParameters, err := transactionFilterSchema.Parse(request)
+	assert.Nil(t, err)
+	sortSpecs, err := transactionSortSchema.Parse(request)
+	assert.Nil(t, err)
+
+	query := repository.Query{
+		Table:       "transactions",
+		Columns:     repository.MergeColumns(transactionFilterSchema.Columns(), transactionSortSchema.Columns(), repository.NewColumns("id")),
+		Filters:     filterParameters,
+		Sort:        sortSpecs,
+		Tiebreakers: []string{"id"},
+	}
+	statement, args, err := query.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM `transactions` WHERE `amount` < ? AND `transaction_date` >= ? ORDER BY `transaction_date` DESC, `id` ASC", statement)
+	assert.Equal(t, []interface{}{"100", "2023-01-01"}, args)
+
+	// The cursors hold the field names, not the columns
+	pagination, err := ParsePagination(request, testPaginationConfig)
+	assert.Nil(t, err)
+	page := pagination.NewPage(request, query, []transaction{{"2", "2023-01-02"}, {"1", "2023-01-01"}, {"0", "2023-01-01"}}, transactionKey)
+	assert.Equal(t, []string{"-date", "id"}, cursorOf(t, page.Links.Next).Sort)
+}
+
+func TestSetupSort(t *testing.T) {
+	request := httptest.NewRequest("GET", "/v1/transactions?sort=amount&sort=-id", nil)
+
+	sortSpecs, err := SetupSort(request, "id", "amount")
+	assert.Nil(t, err)
+	assert.Equal(t, []repository.SortSpec{
+		{Field: "amount", Direction: repository.Ascending},
+		{Field: "id", Direction: repository.Descending},
+	}, sortSpecs)
+}
+
+func TestSortSchemaParseInvalid(t *testing.T) {
+	request := httptest.NewRequest("GET", "/v1/transactions?sort=-password,date,,-date", nil)
+
+	sortSpecs, err := transactionSortSchema.Parse(request)
+	assert.Nil(t, sortSpecs)
+
+	var validationError *ValidationError
+	assert.True(t, errors.As(err, &validationError))
+
+	responseMessage := validationError.ResponseMessage
+	assert.Equal(t, string(faults.UnprocessableEntity), responseMessage.ErrorCode)
+	assert.Equal(t, []responses.ResponseMessageDetail{
+		{Issue: string(faults.InvalidParameterValue), Description: "Field value is invalid", Location: responses.QueryParameter, Field: SortParameter, Value: "-password"},
+		{Issue: string(faults.InvalidParameterValue), Description: "Field value is invalid", Location: responses.QueryParameter, Field: SortParameter, Value: "-password,date,,-date"},
+		{Issue: string(faults.InvalidParameterValue), Description: "Field value is invalid", Location: responses.QueryParameter, Field: SortParameter, Value: "-date"},
+	}, responseMessage.Details)
+
+	_, err = transactionSortSchema.Parse(httptest.NewRequest("GET", "/v1/transactions?sort=", nil))
+	assert.True(t, errors.As(err, &validationError))
+	assert.Equal(t, string(faults.InvalidParameterValueBlank), validationError.ResponseMessage.Details[0].Issue)
+}
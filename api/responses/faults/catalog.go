@@ -1,6 +1,9 @@
 package faults
 
-import "errors"
+import (
+	"errors"
+	"strconv"
+)
 
 type ErrorCode string
 
@@ -31,8 +34,10 @@ const (
 	NotAuthorized        ErrorCode = "NOT_AUTHORIZED"         // HTTP 403 - Authorization failed due to insufficient permissions
 	ResourceNotFound     ErrorCode = "RESOURCE_NOT_FOUND"     // HTTP 404 - The specified resource does not found
 	MethodNotAllowed     ErrorCode = "METHOD_NOT_ALLOWED"     // HTTP 405 - Invalid path and HTTP method combination
+	NotAcceptable        ErrorCode = "NOT_ACCEPTABLE"         // HTTP 406 - The server cannot produce a response matching the accepted media types
 	Conflict             ErrorCode = "CONFLICT"               // HTTP 409 - The request could not be completed due to a conflict with the current state of the target resource
 	UnsupportedMediaType ErrorCode = "UNSUPPORTED_MEDIA_TYPE" // HTTP 415 - The server does not support the request body media type
+	TooManyRequests      ErrorCode = "TOO_MANY_REQUESTS"      // HTTP 429 - The client has sent too many requests in a given amount of time
 	InternalServerError  ErrorCode = "INTERNAL_SERVER_ERROR"  // HTTP 500 - A system or application error occurred
 	BadGateway           ErrorCode = "BAD_GATEWAY"            // HTTP 502 - The server returned an invalid response
 	ServiceUnavailable   ErrorCode = "SERVICE_UNAVAILABLE"    // HTTP 503 - The server cannot handle the request for a service due to temporary maintenance
@@ -73,16 +78,23 @@ const (
 	InvalidStringMinLength         Issue = "INVALID_STRING_MIN_LENGTH"
 	InvalidURLValue                Issue = "INVALID_URL_VALUE"
 	InvalidUUIDValue               Issue = "INVALID_UUID_STRING"
+	InvalidCursor                  Issue = "INVALID_CURSOR"
 	AuthenticationFailure          Issue = "AUTHENTICATION_FAILURE"
 	PermissionDenied               Issue = "PERMISSION_DENIED"
 	RequiredScopeMissing           Issue = "REQUIRED_SCOPE_MISSING"
+	MFARequired                    Issue = "MFA_REQUIRED"
+	InvalidCSRFToken               Issue = "INVALID_CSRF_TOKEN"
 	InvalidResourceId              Issue = "INVALID_RESOURCE_ID"
 	InvalidURI                     Issue = "INVALID_URI"
 	NoRecordsFound                 Issue = "NO_RECORDS_FOUND"
 	MethodNotSupported             Issue = "METHOD_NOT_SUPPORTED"
 	EntityWithSameKeyAlreadyExists Issue = "ENTITY_WITH_SAME_KEY_ALREADY_EXISTS"
+	IdempotencyKeyReused           Issue = "IDEMPOTENCY_KEY_REUSED"
+	IdempotentRequestInProgress    Issue = "IDEMPOTENT_REQUEST_IN_PROGRESS"
 	MissingContentType             Issue = "MISSING_CONTENT_TYPE"
 	InvalidContentType             Issue = "INVALID_CONTENT_TYPE"
+	InvalidAcceptType              Issue = "INVALID_ACCEPT_TYPE"
+	UnsupportedVersion             Issue = "UNSUPPORTED_VERSION"
 
 	// Period API codes
 	OverlappingPeriodDates      Issue = "OVERLAPPING_PERIOD_DATES"
@@ -360,6 +372,14 @@ var catalog = Catalog{
 					FieldRequired:    true,
 					ValueRequired:    true,
 				},
+				{
+					Issue:            InvalidCursor,
+					Description:      "The cursor is invalid or does not match the request",
+					DescriptionArgs:  0,
+					LocationRequired: true,
+					FieldRequired:    true,
+					ValueRequired:    true,
+				},
 				{
 					Issue:            OverlappingPeriodDates,
 					Description:      "There was a date overlapping between the informed period and an existing one",
@@ -414,6 +434,22 @@ var catalog = Catalog{
 					FieldRequired:    false,
 					ValueRequired:    false,
 				},
+				{
+					Issue:            MFARequired,
+					Description:      "Multi-factor authentication is required to access this resource",
+					DescriptionArgs:  0,
+					LocationRequired: false,
+					FieldRequired:    false,
+					ValueRequired:    false,
+				},
+				{
+					Issue:            InvalidCSRFToken,
+					Description:      "The CSRF token is missing or does not match the CSRF cookie",
+					DescriptionArgs:  0,
+					LocationRequired: true,
+					FieldRequired:    true,
+					ValueRequired:    false,
+				},
 			},
 		},
 		{
@@ -462,6 +498,29 @@ var catalog = Catalog{
 				},
 			},
 		},
+		{
+			ErrorCode:      NotAcceptable,
+			Message:        "The server cannot produce a response matching the accepted media types",
+			HttpStatusCode: 406,
+			Details: []ReferenceResponseDetail{
+				{
+					Issue:            InvalidAcceptType,
+					Description:      "None of the media types of the Accept header can be produced",
+					DescriptionArgs:  0,
+					LocationRequired: true,
+					FieldRequired:    true,
+					ValueRequired:    false,
+				},
+				{
+					Issue:            UnsupportedVersion,
+					Description:      "The requested API version is not supported",
+					DescriptionArgs:  0,
+					LocationRequired: true,
+					FieldRequired:    true,
+					ValueRequired:    true,
+				},
+			},
+		},
 		{
 			ErrorCode:      Conflict,
 			Message:        "The request could not be completed due to a conflict with the current state of the target resource",
@@ -475,6 +534,22 @@ var catalog = Catalog{
 					FieldRequired:    true,
 					ValueRequired:    true,
 				},
+				{
+					Issue:            IdempotencyKeyReused,
+					Description:      "The idempotency key was already used with a different request payload",
+					DescriptionArgs:  0,
+					LocationRequired: true,
+					FieldRequired:    true,
+					ValueRequired:    true,
+				},
+				{
+					Issue:            IdempotentRequestInProgress,
+					Description:      "A request with the same idempotency key is still being processed",
+					DescriptionArgs:  0,
+					LocationRequired: true,
+					FieldRequired:    true,
+					ValueRequired:    true,
+				},
 			},
 		},
 		{
@@ -500,6 +575,11 @@ var catalog = Catalog{
 				},
 			},
 		},
+		{
+			ErrorCode:      TooManyRequests,
+			Message:        "The client has sent too many requests in a given amount of time",
+			HttpStatusCode: 429,
+		},
 		{
 			ErrorCode:      InternalServerError,
 			Message:        "A system or application error occurred",
@@ -523,6 +603,11 @@ var catalog = Catalog{
 	},
 }
 
+// List returns the catalog entries (e.g. to document the error responses)
+func List() []ReferenceResponse {
+	return append([]ReferenceResponse{}, catalog.List...)
+}
+
 func FindByErrorCode(errorCode ErrorCode) (ReferenceResponse, error) {
 	for _, value := range catalog.List {
 		if value.ErrorCode == errorCode {
@@ -542,3 +627,12 @@ func FindByIssue(issue Issue) (ReferenceResponse, ReferenceResponseDetail, error
 	}
 	return ReferenceResponse{}, ReferenceResponseDetail{}, errors.New("issue not found" + string(issue))
 }
+
+func FindByHttpStatusCode(httpStatusCode int) (ReferenceResponse, error) {
+	for _, value := range catalog.List {
+		if value.HttpStatusCode == httpStatusCode {
+			return value, nil
+		}
+	}
+	return ReferenceResponse{}, errors.New("http status code not found: " + strconv.Itoa(httpStatusCode))
+}
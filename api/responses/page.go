@@ -0,0 +1,33 @@
+package responses
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Page is the envelope of the list endpoints. Total is only sent when counted
+type Page struct {
+	Items interface{} `json:"items"`
+	Total *int        `json:"total,omitempty"`
+	Links PageLinks   `json:"links"`
+}
+
+// PageLinks are the URIs of the next and previous pages, empty on the last and first pages
+type PageLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+// SetLinkHeader sets the Link header (RFC 8288) with the next and previous pages
+func (page Page) SetLinkHeader(header http.Header) {
+	var links []string
+	if page.Links.Next != "" {
+		links = append(links, `<`+page.Links.Next+`>; rel="next"`)
+	}
+	if page.Links.Prev != "" {
+		links = append(links, `<`+page.Links.Prev+`>; rel="prev"`)
+	}
+	if len(links) > 0 {
+		header.Set("Link", strings.Join(links, ", "))
+	}
+}
@@ -0,0 +1,30 @@
+package responses
+
+import "net/http"
+
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// Problem is the RFC 7807 representation of a ResponseMessage. The catalog error code and details are kept
+// as extension members
+type Problem struct {
+	Type      string                  `json:"type"`
+	Title     string                  `json:"title"`
+	Status    int                     `json:"status"`
+	Detail    string                  `json:"detail,omitempty"`
+	Instance  string                  `json:"instance,omitempty"`
+	ErrorCode string                  `json:"error_code"`
+	Details   []ResponseMessageDetail `json:"details,omitempty"`
+}
+
+// Problem converts the message to the problem details format. Instance identifies the occurrence (e.g. the request URI)
+func (responseMessage *ResponseMessage) Problem(instance string) Problem {
+	return Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(responseMessage.HttpStatusCode),
+		Status:    responseMessage.HttpStatusCode,
+		Detail:    responseMessage.Message,
+		Instance:  instance,
+		ErrorCode: responseMessage.ErrorCode,
+		Details:   responseMessage.Details,
+	}
+}
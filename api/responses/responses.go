@@ -1,13 +1,17 @@
 package responses
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strings"
 
 	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
 	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/logger"
 	"github.com/marcelofelixsalgado/financial-commons/pkg/usecase/status"
+	"github.com/sirupsen/logrus"
 )
 
 type IResponseMessage interface {
@@ -17,11 +21,16 @@ type IResponseMessage interface {
 	Write(http.ResponseWriter)
 }
 
+var placeholderRegexp = regexp.MustCompile(`\{[^}]+\}|\[[^\]]+\]`)
+
 type ResponseMessage struct {
 	HttpStatusCode int                     `json:"-"`
 	ErrorCode      string                  `json:"error_code"`
 	Message        string                  `json:"message"`
 	Details        []ResponseMessageDetail `json:"details,omitempty"`
+
+	// Context of the request, which adds the request and correlation ids to the logged errors
+	ctx context.Context
 }
 
 type ResponseMessageDetail struct {
@@ -45,6 +54,12 @@ func NewResponseMessage() *ResponseMessage {
 	return &ResponseMessage{}
 }
 
+// NewResponseMessageWithContext creates a message whose errors are logged with the values carried by the context
+// (request and correlation ids)
+func NewResponseMessageWithContext(ctx context.Context) *ResponseMessage {
+	return &ResponseMessage{ctx: ctx}
+}
+
 func (responseMessage *ResponseMessage) GetMessage() ResponseMessage {
 	return ResponseMessage{
 		HttpStatusCode: responseMessage.HttpStatusCode,
@@ -66,8 +81,8 @@ func (responseMessage *ResponseMessage) GetMessage() ResponseMessage {
 func (responseMessage *ResponseMessage) AddMessageByErrorCode(errorCode faults.ErrorCode) *ResponseMessage {
 	referenceMessage, err := faults.FindByErrorCode(errorCode)
 	if err != nil {
-		logger.GetLogger().Errorf("Error trying to find the error by code: [%v]: - %v", errorCode, err)
-		return NewResponseMessage().AddMessageByErrorCode(faults.InternalServerError)
+		responseMessage.logger().Errorf("Error trying to find the error by code: [%v]: - %v", errorCode, err)
+		return NewResponseMessageWithContext(responseMessage.ctx).AddMessageByErrorCode(faults.InternalServerError)
 	}
 
 	responseMessage.ErrorCode = string(referenceMessage.ErrorCode)
@@ -81,25 +96,25 @@ func (responseMessage *ResponseMessage) AddMessageByIssue(issue faults.Issue, lo
 
 	referenceResponse, referenceResponseDetail, err := faults.FindByIssue(issue)
 	if err != nil {
-		logger.GetLogger().Errorf("Error trying to find the error by issue: [%v] - %v", issue, err)
-		return NewResponseMessage().AddMessageByErrorCode(faults.InternalServerError)
+		responseMessage.logger().Errorf("Error trying to find the error by issue: [%v] - %v", issue, err)
+		return NewResponseMessageWithContext(responseMessage.ctx).AddMessageByErrorCode(faults.InternalServerError)
 	}
 
 	if referenceResponseDetail.LocationRequired && location == "" {
-		logger.GetLogger().Errorf("Error trying to define a response message - location is required")
-		return NewResponseMessage().AddMessageByErrorCode(faults.InternalServerError)
+		responseMessage.logger().Errorf("Error trying to define a response message - location is required")
+		return NewResponseMessageWithContext(responseMessage.ctx).AddMessageByErrorCode(faults.InternalServerError)
 	}
 	if referenceResponseDetail.FieldRequired && field == "" {
-		logger.GetLogger().Errorf("Error trying to define a response message - field is required")
-		return NewResponseMessage().AddMessageByErrorCode(faults.InternalServerError)
+		responseMessage.logger().Errorf("Error trying to define a response message - field is required")
+		return NewResponseMessageWithContext(responseMessage.ctx).AddMessageByErrorCode(faults.InternalServerError)
 	}
 	if referenceResponseDetail.ValueRequired && value == "" {
-		logger.GetLogger().Errorf("Error trying to define a response message - value is required")
-		return NewResponseMessage().AddMessageByErrorCode(faults.InternalServerError)
+		responseMessage.logger().Errorf("Error trying to define a response message - value is required")
+		return NewResponseMessageWithContext(responseMessage.ctx).AddMessageByErrorCode(faults.InternalServerError)
 	}
 	if referenceResponseDetail.DescriptionArgs != len(descriptionArgs) {
-		logger.GetLogger().Errorf("Error trying to define a response message - wrong number of argumentos passed. expected: [%d] - received: [%d]", referenceResponseDetail.DescriptionArgs, len(descriptionArgs))
-		return NewResponseMessage().AddMessageByErrorCode(faults.InternalServerError)
+		responseMessage.logger().Errorf("Error trying to define a response message - wrong number of argumentos passed. expected: [%d] - received: [%d]", referenceResponseDetail.DescriptionArgs, len(descriptionArgs))
+		return NewResponseMessageWithContext(responseMessage.ctx).AddMessageByErrorCode(faults.InternalServerError)
 	}
 
 	// If responseMessage doesn't exists yet
@@ -139,12 +154,26 @@ func (responseMessage *ResponseMessage) AddMessageByInternalStatus(internalStatu
 func (responseMessage *ResponseMessage) Write(w http.ResponseWriter) {
 	w.WriteHeader(responseMessage.GetMessage().HttpStatusCode)
 	if err := json.NewEncoder(w).Encode(responseMessage.GetMessage()); err != nil {
-		logger.GetLogger().Errorf("Error trying to encode response body message: %v", err)
+		responseMessage.logger().Errorf("Error trying to encode response body message: %v", err)
 	}
 }
 
 func buildMessageDetail(issue faults.Issue, description string, location Location, field string, value string, descriptionArgs []string) ResponseMessageDetail {
 
+	// Descriptions use either printf verbs or named placeholders ({max}, [N]...), replaced in order
+	if !strings.Contains(description, "%") {
+		args := descriptionArgs
+		description = placeholderRegexp.ReplaceAllStringFunc(description, func(placeholder string) string {
+			if len(args) == 0 {
+				return placeholder
+			}
+			arg := args[0]
+			args = args[1:]
+			return arg
+		})
+		descriptionArgs = nil
+	}
+
 	switch len(descriptionArgs) {
 	case 1:
 		description = fmt.Sprintf(description, descriptionArgs[0])
@@ -164,3 +193,10 @@ func buildMessageDetail(issue faults.Issue, description string, location Locatio
 		Value:       value,
 	}
 }
+
+func (responseMessage *ResponseMessage) logger() *logrus.Entry {
+	if responseMessage.ctx == nil {
+		return logger.GetLogger()
+	}
+	return logger.GetLoggerWithContext(responseMessage.ctx)
+}
@@ -1,12 +1,19 @@
 package responses_test
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	. "github.com/marcelofelixsalgado/financial-commons/api/responses"
 	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/correlation"
+	"github.com/marcelofelixsalgado/financial-commons/settings"
 )
 
 const fieldDoesNotSupportDecimals = "Field value does not support decimals"
@@ -97,3 +104,62 @@ func TestGetMessagesReplacementSuccess(t *testing.T) {
 func formatMessageDiff(expectedMessage ResponseMessage, actualMessage *ResponseMessage) string {
 	return fmt.Sprintf("Expected message: [%+v] is not equal Returned Message: [%+v]", expectedMessage, actualMessage)
 }
+
+func TestGetMessagesNamedPlaceholders(t *testing.T) {
+
+	tests := []struct {
+		issue               faults.Issue
+		descriptionArgs     []string
+		expectedDescription string
+	}{
+		{faults.InvalidStringMaxLength, []string{"255"}, "Field value exceeded the maximum allowed number of 255 characters"},
+		{faults.InvalidStringMinLength, []string{"3"}, "Field value should be at least 3 characters"},
+		{faults.FieldValueTooHigh, []string{"100"}, "Field value cannot be higher than 100"},
+		{faults.InvalidArrayMinItems, []string{"1"}, "The number of array items cannot be lower than 1"},
+		{faults.InvalidParameterFormat, []string{"YYYY-MM-DD"}, "Field value does not conform to the expected format: YYYY-MM-DD"},
+		{faults.InvalidDecimalValue, []string{"15", "2"}, "Field value is invalid. It should have a value with maximum 15 digits and 2 fractions"},
+	}
+
+	for _, test := range tests {
+		actualMessage := NewResponseMessage()
+		actualMessage.AddMessageByIssue(test.issue, Body, "field1", "value1", test.descriptionArgs...)
+
+		if len(actualMessage.Details) != 1 || actualMessage.Details[0].Description != test.expectedDescription {
+			t.Errorf("Expected description: [%s] - Returned Message: [%+v]", test.expectedDescription, actualMessage)
+		}
+	}
+}
+
+func TestPageSetLinkHeader(t *testing.T) {
+	header := http.Header{}
+	page := Page{Links: PageLinks{Next: "/v1/transactions?offset=20", Prev: "/v1/transactions?offset=0"}}
+	page.SetLinkHeader(header)
+
+	expectedLink := `</v1/transactions?offset=20>; rel="next", </v1/transactions?offset=0>; rel="prev"`
+	if header.Get("Link") != expectedLink {
+		t.Errorf("expected Link header [%s] - received: [%s]", expectedLink, header.Get("Link"))
+	}
+
+	header = http.Header{}
+	Page{}.SetLinkHeader(header)
+	if _, ok := header["Link"]; ok {
+		t.Errorf("expected no Link header on a single page - received: [%s]", header.Get("Link"))
+	}
+}
+
+func TestResponseMessageWithContextLogsIds(t *testing.T) {
+	// The first test of the package which logs, so it initializes the logger
+	settings.Config.LogLevel = "error"
+	settings.Config.LogAppFile = filepath.Join(t.TempDir(), "app.log")
+
+	ctx := correlation.NewContext(context.Background(), correlation.IDs{RequestID: "request-1", CorrelationID: "correlation-1"})
+	actualMessage := NewResponseMessageWithContext(ctx).AddMessageByIssue(faults.InvalidStringMaxLength, Header, "Idempotency-Key", "")
+
+	if actualMessage.ErrorCode != string(faults.InternalServerError) {
+		t.Errorf("expected error code [%s] - received: [%s]", faults.InternalServerError, actualMessage.ErrorCode)
+	}
+	logs, err := os.ReadFile(settings.Config.LogAppFile)
+	if err != nil || !strings.Contains(string(logs), "request-1") || !strings.Contains(string(logs), "correlation-1") {
+		t.Errorf("expected the request and correlation ids on the log - received: [%s] %v", logs, err)
+	}
+}
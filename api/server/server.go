@@ -0,0 +1,189 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/middlewares"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/logger"
+	"github.com/marcelofelixsalgado/financial-commons/settings"
+)
+
+// Worker runs in background while the server is up (e.g. a Kafka consumer). It must return when the context is done
+type Worker func(ctx context.Context) error
+
+type resource struct {
+	name  string
+	close func() error
+}
+
+type worker struct {
+	name string
+	run  Worker
+}
+
+type Server struct {
+	Echo *echo.Echo
+
+	resources []resource
+	workers   []worker
+}
+
+// New creates the echo server with the standard middleware chain: request id, panic recovery, custom context and
+// access log, answering the errors with the fault catalog
+func New() *Server {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.HTTPErrorHandler = middlewares.HTTPErrorHandler
+	e.IPExtractor = NewIPExtractor(settings.Config)
+	e.Use(
+		middlewares.RequestID(),
+		// Outermost (after the request id, which it logs), so the panics of every middleware become a 500 response
+		middlewares.Recover(),
+		middlewares.CustomContext(),
+		middlewares.Logger(),
+		// Also inside the logger, so the panics of the handlers are logged with the 500 status
+		middlewares.Recover(),
+	)
+
+	return &Server{
+		Echo: e,
+	}
+}
+
+// NewIPExtractor resolves the client IP (rate limits, logs and traces) from the connection, or from the X-Forwarded-For
+// header when the request comes through the trusted proxies. The client headers are never trusted otherwise. Panics
+// when a trusted proxy is not a valid CIDR
+func NewIPExtractor(config settings.ConfigType) echo.IPExtractor {
+	if len(config.TrustedProxies) == 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+	for _, proxy := range config.TrustedProxies {
+		_, ipRange, err := net.ParseCIDR(strings.TrimSpace(proxy))
+		if err != nil {
+			panic(fmt.Sprintf("invalid trusted proxy [%s]: %v", proxy, err))
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// AddResource registers a resource (database, producer...) closed on shutdown, in the order of registration
+func (server *Server) AddResource(name string, close func() error) *Server {
+	server.resources = append(server.resources, resource{name: name, close: close})
+	return server
+}
+
+// AddWorker registers a background worker, started with the server and stopped before the resources are closed:
+//
+//	server.AddWorker("transactions consumer", func(ctx context.Context) error {
+//		return consumer.ConsumeWithContext(ctx, handler)
+//	})
+func (server *Server) AddWorker(name string, run Worker) *Server {
+	server.workers = append(server.workers, worker{name: name, run: run})
+	return server
+}
+
+// Start runs the server on settings.Config.ApiHttpPort until SIGINT or SIGTERM is received
+func (server *Server) Start() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return server.Run(ctx)
+}
+
+// Run runs the server until the context is done. Then it stops accepting connections, waits up to
+// settings.Config.ServerCloseWait seconds for the requests in flight and the workers, and closes the resources
+func (server *Server) Run(ctx context.Context) error {
+	log := logger.GetLogger()
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	workersDone := server.startWorkers(workersCtx)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		address := fmt.Sprintf(":%d", settings.Config.ApiHttpPort)
+		log.Infof("Starting server on %s", address)
+		if err := server.Echo.Start(address); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		log.Info("Shutting down the server")
+	case runErr = <-serverErr:
+		log.Errorf("Error running the server: %v", runErr)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(settings.Config.ServerCloseWait)*time.Second)
+	defer cancel()
+
+	errs := []error{runErr}
+	if err := server.Echo.Shutdown(shutdownCtx); err != nil {
+		log.Errorf("Error waiting for the requests in flight: %v", err)
+		errs = append(errs, err)
+	}
+
+	stopWorkers()
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		log.Error("Timeout waiting for the workers to stop")
+		errs = append(errs, shutdownCtx.Err())
+	}
+
+	errs = append(errs, server.closeResources()...)
+	if err := middlewares.CloseAccessLog(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (server *Server) startWorkers(ctx context.Context) <-chan struct{} {
+	var wg sync.WaitGroup
+	for _, w := range server.workers {
+		wg.Add(1)
+		go func(w worker) {
+			defer wg.Done()
+			if err := w.run(ctx); err != nil {
+				logger.GetLogger().Errorf("Error running the worker [%s]: %v", w.name, err)
+			}
+		}(w)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return done
+}
+
+func (server *Server) closeResources() []error {
+	var errs []error
+	for _, r := range server.resources {
+		if err := r.close(); err != nil {
+			logger.GetLogger().Errorf("Error closing [%s]: %v", r.name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", r.name, err))
+			continue
+		}
+		logger.GetLogger().Infof("[%s] closed", r.name)
+	}
+	return errs
+}
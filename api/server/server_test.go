@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/api/middlewares"
+	"github.com/marcelofelixsalgado/financial-commons/api/responses/faults"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/ratelimit"
+	"github.com/marcelofelixsalgado/financial-commons/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServerRun(t *testing.T) {
+	settings.Config.LogLevel = "error"
+	settings.Config.LogAppFile = filepath.Join(t.TempDir(), "app.log")
+	settings.Config.LogAccessFile = filepath.Join(t.TempDir(), "access.log")
+	settings.Config.ApiHttpPort = 0
+	settings.Config.ServerCloseWait = 5
+
+	var closed []string
+	workerStopped := make(chan struct{})
+
+	server := New().
+		AddResource("database", func() error {
+			closed = append(closed, "database")
+			return nil
+		}).
+		AddResource("producer", func() error {
+			closed = append(closed, "producer")
+			return nil
+		}).
+		AddWorker("consumer", func(ctx context.Context) error {
+			<-ctx.Done()
+			closed = append(closed, "consumer")
+			close(workerStopped)
+			return nil
+		})
+	server.Echo.GET("/v1/ping", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- server.Run(ctx)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return server.Echo.ListenerAddr() != nil
+	}, time.Second, 10*time.Millisecond)
+
+	response, err := http.Get("http://" + server.Echo.ListenerAddr().String() + "/v1/ping")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusNoContent, response.StatusCode)
+	assert.NotEmpty(t, response.Header.Get(echo.HeaderXRequestID))
+	response.Body.Close()
+
+	cancel()
+	select {
+	case err := <-runErr:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop")
+	}
+
+	<-workerStopped
+	assert.Equal(t, []string{"consumer", "database", "producer"}, closed)
+}
+
+func TestServerIPExtractor(t *testing.T) {
+	settings.Config.LogLevel = "error"
+	settings.Config.LogAppFile = filepath.Join(t.TempDir(), "app.log")
+	settings.Config.LogAccessFile = filepath.Join(t.TempDir(), "access.log")
+	settings.Config.TrustedProxies = nil
+
+	server := New()
+	server.Echo.GET("/v1/ping", func(c echo.Context) error {
+		return c.String(http.StatusOK, c.RealIP())
+	}, middlewares.RateLimit(middlewares.RateLimitConfig{Policy: ratelimit.Policy{
+		Name:      "ping",
+		Algorithm: ratelimit.SlidingWindow,
+		Key:       ratelimit.ByIP,
+		Limit:     1,
+		Period:    time.Minute,
+	}}))
+
+	// A spoofed X-Forwarded-For header does not reset the limit of the client
+	var statuses []int
+	for _, forwardedFor := range []string{"203.0.113.1", "203.0.113.2"} {
+		request := httptest.NewRequest(http.MethodGet, "/v1/ping", nil)
+		request.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+		request.Header.Set(echo.HeaderXRealIP, forwardedFor)
+		recorder := httptest.NewRecorder()
+		server.Echo.ServeHTTP(recorder, request)
+		statuses = append(statuses, recorder.Code)
+		if recorder.Code == http.StatusOK {
+			assert.Equal(t, "192.0.2.1", recorder.Body.String())
+		}
+	}
+	assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests}, statuses)
+}
+
+func TestNewIPExtractor(t *testing.T) {
+	extractor := NewIPExtractor(settings.ConfigType{TrustedProxies: []string{"192.0.2.0/24"}})
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/ping", nil)
+	request.Header.Set(echo.HeaderXForwardedFor, "203.0.113.1")
+	assert.Equal(t, "203.0.113.1", extractor(request))
+
+	request.RemoteAddr = "198.51.100.1:1234"
+	assert.Equal(t, "198.51.100.1", extractor(request))
+
+	assert.Panics(t, func() {
+		NewIPExtractor(settings.ConfigType{TrustedProxies: []string{"10.0.0.1"}})
+	})
+}
+
+func TestServerRecover(t *testing.T) {
+	settings.Config.LogLevel = "error"
+	settings.Config.LogAppFile = filepath.Join(t.TempDir(), "app.log")
+	settings.Config.LogAccessFile = filepath.Join(t.TempDir(), "access.log")
+
+	server := New()
+	server.Echo.GET("/v1/panic", func(c echo.Context) error {
+		panic("boom")
+	})
+
+	recorder := httptest.NewRecorder()
+	assert.NotPanics(t, func() {
+		server.Echo.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/panic", nil))
+	})
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), string(faults.InternalServerError))
+	assert.NotEmpty(t, recorder.Header().Get(echo.HeaderXRequestID))
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// Prefix added to the generated keys, so they are easily identified (e.g. by secret scanners)
+const apiKeyPrefix = "fk_"
+
+var (
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrInvalidAPIKey      = errors.New("invalid api key")
+	ErrAPIKeyStoreMissing = errors.New("api key store not configured")
+)
+
+// APIKey is the stored representation of a key. Only the hash of the key is ever persisted
+type APIKey struct {
+	Id        string
+	ClientId  string
+	TenantId  string
+	Hash      string
+	Scopes    []string
+	ExpiresAt time.Time // zero value means the key never expires
+	Revoked   bool
+}
+
+type IAPIKeyStore interface {
+	FindByHash(hash string) (APIKey, error)
+}
+
+// GenerateAPIKey returns a new random key and its hash. The key must be shown to the client only once
+func GenerateAPIKey() (string, string, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", "", err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(randomBytes)
+	return key, HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hex encoded SHA-256 hash of the key
+func HashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+// ValidateAPIKey checks the key sent on the request header against the store
+func ValidateAPIKey(r *http.Request, store IAPIKeyStore) (Identity, error) {
+	if store == nil {
+		return Identity{}, ErrAPIKeyStoreMissing
+	}
+
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return Identity{}, ErrMissingCredentials
+	}
+
+	hash := HashAPIKey(key)
+	apiKey, err := store.FindByHash(hash)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(apiKey.Hash), []byte(hash)) != 1 || apiKey.Revoked {
+		return Identity{}, ErrInvalidAPIKey
+	}
+	if !apiKey.ExpiresAt.IsZero() && time.Now().After(apiKey.ExpiresAt) {
+		return Identity{}, ErrInvalidAPIKey
+	}
+
+	return Identity{
+		Type:     ServiceIdentity,
+		Scheme:   APIKeyScheme,
+		Subject:  apiKey.ClientId,
+		TenantId: apiKey.TenantId,
+		Scopes:   apiKey.Scopes,
+	}, nil
+}
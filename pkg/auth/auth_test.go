@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/marcelofelixsalgado/financial-commons/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+type apiKeyStoreStub struct {
+	keys map[string]APIKey
+}
+
+func (s apiKeyStoreStub) FindByHash(hash string) (APIKey, error) {
+	if apiKey, ok := s.keys[hash]; ok {
+		return apiKey, nil
+	}
+	return APIKey{}, errors.New("not found")
+}
+
+type clientStoreStub struct {
+	client Client
+}
+
+func (s clientStoreStub) FindByClientId(clientId string) (Client, error) {
+	if clientId == s.client.ClientId {
+		return s.client, nil
+	}
+	return Client{}, errors.New("not found")
+}
+
+func TestValidateAPIKey(t *testing.T) {
+	key, hash, err := GenerateAPIKey()
+	assert.Nil(t, err)
+
+	expiredKey, expiredHash, _ := GenerateAPIKey()
+
+	store := apiKeyStoreStub{keys: map[string]APIKey{
+		hash:        {Id: "1", ClientId: "importer", TenantId: "tenant-1", Hash: hash, Scopes: []string{"transactions:write"}},
+		expiredHash: {Id: "2", ClientId: "importer", Hash: expiredHash, ExpiresAt: time.Now().Add(-time.Hour)},
+	}}
+
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set(APIKeyHeader, key)
+	identity, err := ValidateAPIKey(request, store)
+	assert.Nil(t, err)
+	assert.Equal(t, ServiceIdentity, identity.Type)
+	assert.Equal(t, "importer", identity.Subject)
+	assert.Equal(t, "tenant-1", identity.TenantId)
+	assert.True(t, identity.HasScopes("transactions:write"))
+	assert.False(t, identity.HasScopes("transactions:read"))
+
+	request.Header.Set(APIKeyHeader, expiredKey)
+	_, err = ValidateAPIKey(request, store)
+	assert.Equal(t, ErrInvalidAPIKey, err)
+}
+
+func TestIssueClientToken(t *testing.T) {
+	settings.Config.SecretKey = []byte("secret")
+	settings.Config.ClientTokenExpiration = 60
+
+	secretHash, err := HashClientSecret("client-secret")
+	assert.Nil(t, err)
+	assert.Regexp(t, `^\$argon2id\$`, secretHash)
+
+	store := clientStoreStub{client: Client{
+		ClientId:   "importer",
+		SecretHash: secretHash,
+		TenantId:   "tenant-1",
+		Scopes:     []string{"transactions:read", "transactions:write"},
+	}}
+
+	_, err = IssueClientToken(store, "importer", "wrong-secret", nil)
+	assert.Equal(t, ErrInvalidClient, err)
+
+	// Secrets hashed with the unsalted API key hash are not accepted
+	legacyStore := clientStoreStub{client: Client{ClientId: "importer", SecretHash: HashAPIKey("client-secret")}}
+	_, err = IssueClientToken(legacyStore, "importer", "client-secret", nil)
+	assert.Equal(t, ErrInvalidClient, err)
+
+	_, err = IssueClientToken(store, "importer", "client-secret", []string{"admin"})
+	assert.Equal(t, ErrInvalidScope, err)
+
+	token, err := IssueClientToken(store, "importer", "client-secret", []string{"transactions:read"})
+	assert.Nil(t, err)
+
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	identity, err := Authenticate(request, []Scheme{APIKeyScheme, BearerScheme}, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, ServiceIdentity, identity.Type)
+	assert.Equal(t, "importer", identity.Subject)
+	assert.Equal(t, []string{"transactions:read"}, identity.Scopes)
+	assert.Equal(t, token, identity.AccessToken())
+}
+
+func TestAuthenticateWithExtractor(t *testing.T) {
+	settings.Config.SecretKey = []byte("secret")
+
+	token, err := CreateToken("user-1", "tenant-1")
+	assert.Nil(t, err)
+
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	request.AddCookie(&http.Cookie{Name: SessionCookieName, Value: token})
+
+	// The default extractor only reads the Authorization header
+	_, err = Authenticate(request, nil, nil, nil)
+	assert.Equal(t, ErrMissingCredentials, err)
+
+	identity, err := Authenticate(request, nil, nil, CookieExtractor{Name: SessionCookieName})
+	assert.Nil(t, err)
+	assert.Equal(t, "user-1", identity.Subject)
+	assert.Equal(t, token, identity.AccessToken())
+}
+
+func TestGenerateClientSecret(t *testing.T) {
+	secret, hash, err := GenerateClientSecret()
+	assert.Nil(t, err)
+	assert.Regexp(t, `^fcs_[A-Za-z0-9_-]{43}$`, secret)
+
+	match, _, err := clientSecretHasher.Verify(secret, hash)
+	assert.Nil(t, err)
+	assert.True(t, match)
+}
+
+func TestTokenExtractors(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "/?access_token=query-token", nil)
+
+	_, err := NewHeaderExtractor().Extract(request)
+	assert.Equal(t, ErrTokenNotFound, err)
+
+	request.Header.Set("Authorization", "Bearer")
+	_, err = NewHeaderExtractor().Extract(request)
+	assert.Equal(t, ErrMalformedAuthToken, err)
+
+	request.Header.Set("Authorization", "bearer  header-token")
+	token, err := NewHeaderExtractor().Extract(request)
+	assert.Nil(t, err)
+	assert.Equal(t, "header-token", token)
+
+	request.Header.Del("Authorization")
+	request.AddCookie(&http.Cookie{Name: SessionCookieName, Value: "cookie-token"})
+	chain := ChainExtractor{NewHeaderExtractor(), CookieExtractor{Name: SessionCookieName}, QueryExtractor{Parameter: "access_token"}}
+	token, err = chain.Extract(request)
+	assert.Nil(t, err)
+	assert.Equal(t, "cookie-token", token)
+
+	token, err = QueryExtractor{Parameter: "access_token"}.Extract(request)
+	assert.Nil(t, err)
+	assert.Equal(t, "query-token", token)
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/auth/password"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tenant"
+	"github.com/marcelofelixsalgado/financial-commons/settings"
+)
+
+const (
+	clientIdClaim = "clientId"
+	scopeClaim    = "scope"
+
+	// Prefix added to the generated secrets, so they are easily identified (e.g. by secret scanners)
+	clientSecretPrefix = "fcs_"
+)
+
+// The client secrets are hashed as passwords (salted, slow hash), since they may not be generated by GenerateClientSecret
+var clientSecretHasher = password.NewDefaultManager()
+
+var (
+	ErrInvalidClient = errors.New("invalid client credentials")
+	ErrInvalidScope  = errors.New("requested scope not granted to the client")
+)
+
+// Client is a registered service identity (e.g. batch importers and other backends)
+type Client struct {
+	ClientId   string
+	SecretHash string // hashed with HashClientSecret
+	TenantId   string
+	Scopes     []string
+}
+
+type IClientStore interface {
+	FindByClientId(clientId string) (Client, error)
+}
+
+// GenerateClientSecret returns a new random secret and its hash. The secret must be shown to the client only once
+func GenerateClientSecret() (string, string, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", "", err
+	}
+	secret := clientSecretPrefix + base64.RawURLEncoding.EncodeToString(randomBytes)
+	hash, err := HashClientSecret(secret)
+	if err != nil {
+		return "", "", err
+	}
+	return secret, hash, nil
+}
+
+// HashClientSecret returns the PHC formatted hash (argon2id) of the secret
+func HashClientSecret(secret string) (string, error) {
+	return clientSecretHasher.Hash(secret)
+}
+
+// CreateClientToken creates a token for a service identity with its own scopes
+func CreateClientToken(clientId string, tenantId string, scopes []string) (string, error) {
+	permissions := jwt.MapClaims{}
+	permissions["Authorized"] = true
+	permissions["exp"] = time.Now().Add(time.Minute * time.Duration(settings.Config.ClientTokenExpiration)).Unix()
+	permissions[clientIdClaim] = clientId
+	permissions[tenant.ClaimName] = tenantId
+	permissions[scopeClaim] = strings.Join(scopes, " ")
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, permissions)
+	jwtToken, err := token.SignedString(settings.Config.SecretKey)
+	if err != nil {
+		return "", err
+	}
+	return jwtToken, nil
+}
+
+// IssueClientToken implements the client credentials grant: it verifies the client secret and
+// issues a token with the requested scopes (or all the client scopes when none is requested)
+func IssueClientToken(store IClientStore, clientId string, clientSecret string, scopes []string) (string, error) {
+	client, err := store.FindByClientId(clientId)
+	if err != nil {
+		return "", ErrInvalidClient
+	}
+
+	match, _, err := clientSecretHasher.Verify(clientSecret, client.SecretHash)
+	if err != nil || !match {
+		return "", ErrInvalidClient
+	}
+
+	if len(scopes) == 0 {
+		scopes = client.Scopes
+	}
+	for _, scope := range scopes {
+		if !contains(client.Scopes, scope) {
+			return "", ErrInvalidScope
+		}
+	}
+
+	return CreateClientToken(client.ClientId, client.TenantId, scopes)
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/marcelofelixsalgado/financial-commons/settings"
+)
+
+const (
+	SessionCookieName = "access_token"
+	CSRFCookieName    = "csrf_token"
+	CSRFHeader        = "X-CSRF-Token"
+)
+
+type CookieConfig struct {
+	Name     string
+	Domain   string
+	Path     string
+	MaxAge   time.Duration
+	Secure   bool
+	HttpOnly bool
+	SameSite http.SameSite
+}
+
+// NewSessionCookieConfig returns the settings for the session (access token) cookie. It can not be read by scripts
+func NewSessionCookieConfig() CookieConfig {
+	return CookieConfig{
+		Name:     SessionCookieName,
+		Domain:   settings.Config.SessionCookieDomain,
+		Path:     "/",
+		MaxAge:   time.Hour * 6,
+		Secure:   settings.Config.SessionCookieSecure,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	}
+}
+
+// NewCSRFCookieConfig returns the settings for the CSRF cookie. It must be readable by the front-end scripts,
+// which send its value back on the CSRFHeader header (double-submit cookie)
+func NewCSRFCookieConfig() CookieConfig {
+	config := NewSessionCookieConfig()
+	config.Name = CSRFCookieName
+	config.HttpOnly = false
+	return config
+}
+
+// SetCookie writes the cookie on the response
+func SetCookie(w http.ResponseWriter, config CookieConfig, value string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     config.Name,
+		Value:    value,
+		Domain:   config.Domain,
+		Path:     config.Path,
+		MaxAge:   int(config.MaxAge.Seconds()),
+		Expires:  time.Now().Add(config.MaxAge),
+		Secure:   config.Secure,
+		HttpOnly: config.HttpOnly,
+		SameSite: config.SameSite,
+	})
+}
+
+// ClearCookie expires the cookie on the client (e.g. on logout)
+func ClearCookie(w http.ResponseWriter, config CookieConfig) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     config.Name,
+		Value:    "",
+		Domain:   config.Domain,
+		Path:     config.Path,
+		MaxAge:   -1,
+		Expires:  time.Unix(0, 0),
+		Secure:   config.Secure,
+		HttpOnly: config.HttpOnly,
+		SameSite: config.SameSite,
+	})
+}
+
+// IssueSessionCookies writes the session cookie with the access token and a new CSRF cookie
+func IssueSessionCookies(w http.ResponseWriter, accessToken string) error {
+	csrfToken, err := GenerateCSRFToken()
+	if err != nil {
+		return err
+	}
+	SetCookie(w, NewSessionCookieConfig(), accessToken)
+	SetCookie(w, NewCSRFCookieConfig(), csrfToken)
+	return nil
+}
+
+// GenerateCSRFToken returns a new random token
+func GenerateCSRFToken() (string, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrTokenNotFound      = errors.New("token not found")
+	ErrMalformedAuthToken = errors.New("malformed authorization header")
+)
+
+// ITokenExtractor reads the access token from the request
+type ITokenExtractor interface {
+	// Extract returns ErrTokenNotFound when the request does not carry a token
+	Extract(r *http.Request) (string, error)
+}
+
+// HeaderExtractor reads tokens sent as "<Scheme> <token>" (e.g. Authorization: Bearer 123)
+type HeaderExtractor struct {
+	Header string
+	Scheme string
+}
+
+// CookieExtractor reads tokens sent on a cookie
+type CookieExtractor struct {
+	Name string
+}
+
+// QueryExtractor reads tokens sent on a query parameter. Only use it when headers can not be sent
+// (e.g. download links and websockets), since the URL ends up in access logs and browser history
+type QueryExtractor struct {
+	Parameter string
+}
+
+// ChainExtractor returns the token found by the first extractor which finds one
+type ChainExtractor []ITokenExtractor
+
+func NewHeaderExtractor() HeaderExtractor {
+	return HeaderExtractor{
+		Header: "Authorization",
+		Scheme: "Bearer",
+	}
+}
+
+// ExtractToken returns the access token sent on the Authorization header. The routes reading the token from elsewhere
+// (e.g. cookie sessions) set the extractor on their authentication config
+func ExtractToken(r *http.Request) (string, error) {
+	return NewHeaderExtractor().Extract(r)
+}
+
+func (extractor HeaderExtractor) Extract(r *http.Request) (string, error) {
+	value := strings.TrimSpace(r.Header.Get(extractor.Header))
+	if value == "" {
+		return "", ErrTokenNotFound
+	}
+
+	parts := strings.Fields(value)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], extractor.Scheme) {
+		return "", ErrMalformedAuthToken
+	}
+	return parts[1], nil
+}
+
+func (extractor CookieExtractor) Extract(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(extractor.Name)
+	if err != nil || cookie.Value == "" {
+		return "", ErrTokenNotFound
+	}
+	return cookie.Value, nil
+}
+
+func (extractor QueryExtractor) Extract(r *http.Request) (string, error) {
+	value := r.URL.Query().Get(extractor.Parameter)
+	if value == "" {
+		return "", ErrTokenNotFound
+	}
+	return value, nil
+}
+
+func (extractors ChainExtractor) Extract(r *http.Request) (string, error) {
+	for _, extractor := range extractors {
+		token, err := extractor.Extract(r)
+		if errors.Is(err, ErrTokenNotFound) {
+			continue
+		}
+		return token, err
+	}
+	return "", ErrTokenNotFound
+}
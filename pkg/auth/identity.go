@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+type Scheme string
+
+const (
+	BearerScheme Scheme = "bearer"  // User and client credentials tokens read by the route ITokenExtractor
+	APIKeyScheme Scheme = "api_key" // Hashed API keys sent on the APIKeyHeader header
+)
+
+type IdentityType string
+
+const (
+	UserIdentity    IdentityType = "user"
+	ServiceIdentity IdentityType = "service"
+)
+
+// Identity is the authenticated caller of a request
+type Identity struct {
+	Type     IdentityType
+	Scheme   Scheme
+	Subject  string // userId for users, clientId for services
+	TenantId string
+	Scopes   []string
+	MFA      bool // user completed the multi-factor authentication
+
+	// Bearer token the identity was authenticated with
+	accessToken string
+}
+
+// AccessToken returns the bearer token the identity was authenticated with, empty for the other schemes
+func (identity Identity) AccessToken() string {
+	return identity.accessToken
+}
+
+// HasScopes checks if the identity was granted all the scopes
+func (identity Identity) HasScopes(scopes ...string) bool {
+	for _, scope := range scopes {
+		if !contains(identity.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+type identityContextKey struct{}
+
+// NewContext returns a copy of the parent context carrying the identity
+func NewContext(parent context.Context, identity Identity) context.Context {
+	return context.WithValue(parent, identityContextKey{}, identity)
+}
+
+// IdentityFromContext returns the identity stored in the context, if any
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityContextKey{}).(Identity)
+	return identity, ok
+}
+
+// Authenticate resolves the caller identity using the first accepted scheme present on the request. The bearer tokens
+// are read by the extractor (the Authorization header when nil)
+func Authenticate(r *http.Request, schemes []Scheme, apiKeyStore IAPIKeyStore, extractor ITokenExtractor) (Identity, error) {
+	if len(schemes) == 0 {
+		schemes = []Scheme{BearerScheme}
+	}
+	if extractor == nil {
+		extractor = NewHeaderExtractor()
+	}
+
+	for _, scheme := range schemes {
+		switch scheme {
+		case BearerScheme:
+			if _, err := extractor.Extract(r); !errors.Is(err, ErrTokenNotFound) {
+				return ExtractIdentityWithExtractor(r, extractor)
+			}
+		case APIKeyScheme:
+			if r.Header.Get(APIKeyHeader) != "" {
+				return ValidateAPIKey(r, apiKeyStore)
+			}
+		}
+	}
+	return Identity{}, ErrMissingCredentials
+}
+
+func contains(values []string, value string) bool {
+	for _, current := range values {
+		if current == value {
+			return true
+		}
+	}
+	return false
+}
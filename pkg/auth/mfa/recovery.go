@@ -0,0 +1,51 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateRecoveryCodes returns one-time recovery codes (xxxxxxxx-xxxxxxxx) and their hashes. Only the hashes must be stored
+func GenerateRecoveryCodes(quantity int) ([]string, []string, error) {
+	codes := make([]string, 0, quantity)
+	hashes := make([]string, 0, quantity)
+
+	for i := 0; i < quantity; i++ {
+		randomBytes := make([]byte, 10)
+		if _, err := rand.Read(randomBytes); err != nil {
+			return nil, nil, err
+		}
+		encoded := strings.ToLower(recoveryCodeEncoding.EncodeToString(randomBytes))
+		code := encoded[:8] + "-" + encoded[8:16]
+
+		codes = append(codes, code)
+		hashes = append(hashes, HashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// HashRecoveryCode normalizes (case and separators) and hashes the recovery code
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
+
+// VerifyRecoveryCode returns the index of the hash matching the code, or -1. The matched hash must be removed by
+// the caller, so the code cannot be used again
+func VerifyRecoveryCode(code string, hashes []string) int {
+	hash := []byte(HashRecoveryCode(code))
+	index := -1
+	for i, current := range hashes {
+		if subtle.ConstantTimeCompare([]byte(current), hash) == 1 {
+			index = i
+		}
+	}
+	return index
+}
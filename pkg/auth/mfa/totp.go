@@ -0,0 +1,158 @@
+package mfa
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Secret length in bytes (160 bits, as recommended by RFC 4226)
+const secretLength = 20
+
+// Number of digits of the codes accepted by the authenticator apps
+const (
+	minDigits = 6
+	maxDigits = 8
+)
+
+var (
+	ErrInvalidSecret   = errors.New("invalid totp secret")
+	ErrInvalidCode     = errors.New("invalid totp code")
+	ErrCodeAlreadyUsed = errors.New("totp code already used")
+	ErrInvalidConfig   = errors.New("invalid totp config: digits must be between 6 and 8 and period greater than zero")
+)
+
+var (
+	secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+	digitsPowers   = []uint32{1, 10, 100, 1000, 10000, 100000, 1000000, 10000000, 100000000}
+)
+
+// TOTP generates and verifies time-based one-time passwords (RFC 6238) using HMAC-SHA1
+type TOTP struct {
+	Issuer string
+	Period uint // seconds
+	Digits int  // 6 to 8
+	Skew   uint // number of periods accepted before and after the current one
+}
+
+func NewTOTP(issuer string) *TOTP {
+	return &TOTP{
+		Issuer: issuer,
+		Period: 30,
+		Digits: 6,
+		Skew:   1,
+	}
+}
+
+// GenerateSecret returns a new random base32 encoded secret
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return secretEncoding.EncodeToString(secret), nil
+}
+
+// ProvisioningURI returns the otpauth:// URI used by authenticator apps (usually rendered as a QR code)
+func (totp *TOTP) ProvisioningURI(secret string, accountName string) string {
+	label := url.PathEscape(accountName)
+	if totp.Issuer != "" {
+		label = url.PathEscape(totp.Issuer) + ":" + label
+	}
+
+	params := url.Values{}
+	params.Set("secret", secret)
+	if totp.Issuer != "" {
+		params.Set("issuer", totp.Issuer)
+	}
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(totp.Digits))
+	params.Set("period", fmt.Sprint(totp.Period))
+
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// GenerateCode returns the code valid at the given time
+func (totp *TOTP) GenerateCode(secret string, t time.Time) (string, error) {
+	if err := totp.validate(); err != nil {
+		return "", err
+	}
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return totp.generate(key, totp.counter(t)), nil
+}
+
+// Verify checks the code against the periods inside the skew window. lastUsedCounter is the counter returned by the
+// last successful verification for the same secret (zero if none); codes from that period or older are rejected to
+// prevent replays. The matched counter is returned and must be stored by the caller.
+func (totp *TOTP) Verify(secret string, code string, t time.Time, lastUsedCounter uint64) (uint64, error) {
+	if err := totp.validate(); err != nil {
+		return 0, err
+	}
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(code) != totp.Digits {
+		return 0, ErrInvalidCode
+	}
+
+	current := totp.counter(t)
+	for offset := -int64(totp.Skew); offset <= int64(totp.Skew); offset++ {
+		counter := uint64(int64(current) + offset)
+		if subtle.ConstantTimeCompare([]byte(totp.generate(key, counter)), []byte(code)) != 1 {
+			continue
+		}
+		if counter <= lastUsedCounter {
+			return 0, ErrCodeAlreadyUsed
+		}
+		return counter, nil
+	}
+	return 0, ErrInvalidCode
+}
+
+// validate checks the config set by the caller, which would otherwise make counter and generate panic
+func (totp *TOTP) validate() error {
+	if totp.Digits < minDigits || totp.Digits > maxDigits || totp.Period == 0 {
+		return ErrInvalidConfig
+	}
+	return nil
+}
+
+func (totp *TOTP) counter(t time.Time) uint64 {
+	return uint64(t.Unix()) / uint64(totp.Period)
+}
+
+// generate implements the HOTP algorithm (RFC 4226)
+func (totp *TOTP) generate(key []byte, counter uint64) string {
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totp.Digits, value%digitsPowers[totp.Digits])
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := secretEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
@@ -0,0 +1,92 @@
+package mfa
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 Appendix B test vectors (SHA1)
+func TestGenerateCodeRFC6238(t *testing.T) {
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	totp := &TOTP{Period: 30, Digits: 8}
+
+	vectors := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+
+	for unixTime, expected := range vectors {
+		code, err := totp.GenerateCode(secret, time.Unix(unixTime, 0))
+		assert.Nil(t, err)
+		assert.Equal(t, expected, code, "time %d", unixTime)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.Nil(t, err)
+
+	totp := NewTOTP("Financial")
+	now := time.Now()
+
+	code, _ := totp.GenerateCode(secret, now.Add(-30*time.Second))
+	counter, err := totp.Verify(secret, code, now, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, totp.counter(now)-1, counter)
+
+	// replay of the same code
+	_, err = totp.Verify(secret, code, now, counter)
+	assert.Equal(t, ErrCodeAlreadyUsed, err)
+
+	// outside the skew window
+	code, _ = totp.GenerateCode(secret, now.Add(-90*time.Second))
+	_, err = totp.Verify(secret, code, now, 0)
+	assert.Equal(t, ErrInvalidCode, err)
+
+	_, err = totp.Verify("not base32!", "123456", now, 0)
+	assert.Equal(t, ErrInvalidSecret, err)
+}
+
+func TestInvalidConfig(t *testing.T) {
+	secret, err := GenerateSecret()
+	assert.Nil(t, err)
+
+	for _, totp := range []*TOTP{{Period: 0, Digits: 6}, {Period: 30, Digits: 9}, {Period: 30, Digits: 5}} {
+		_, err := totp.GenerateCode(secret, time.Now())
+		assert.Equal(t, ErrInvalidConfig, err)
+
+		_, err = totp.Verify(secret, "123456", time.Now(), 0)
+		assert.Equal(t, ErrInvalidConfig, err)
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	totp := NewTOTP("Financial App")
+
+	uri := totp.ProvisioningURI("JBSWY3DPEHPK3PXP", "john@example.com")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Financial%20App:john@example.com?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=Financial+App")
+	assert.Contains(t, uri, "digits=6")
+	assert.Contains(t, uri, "period=30")
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes, err := GenerateRecoveryCodes(10)
+	assert.Nil(t, err)
+	assert.Len(t, codes, 10)
+	assert.Len(t, hashes, 10)
+	assert.Regexp(t, `^[a-z2-7]{8}-[a-z2-7]{8}$`, codes[0])
+
+	assert.Equal(t, 3, VerifyRecoveryCode(strings.ToUpper(codes[3]), hashes))
+	assert.Equal(t, -1, VerifyRecoveryCode("aaaaaaaa-aaaaaaaa", hashes))
+}
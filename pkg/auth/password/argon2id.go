@@ -0,0 +1,119 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+type Argon2idHasher struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+type argon2idHash struct {
+	version     int
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+// NewArgon2idHasher returns a hasher with the parameters recommended by RFC 9106 for memory constrained environments
+func NewArgon2idHasher() *Argon2idHasher {
+	return &Argon2idHasher{
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+}
+
+func (hasher *Argon2idHasher) Algorithm() Algorithm {
+	return Argon2id
+}
+
+func (hasher *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, hasher.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, hasher.Iterations, hasher.Memory, hasher.Parallelism, hasher.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		hasher.Memory,
+		hasher.Iterations,
+		hasher.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (hasher *Argon2idHasher) Verify(password string, encodedHash string) (bool, error) {
+	hash, err := decodeArgon2idHash(encodedHash)
+	if err != nil {
+		return false, err
+	}
+
+	key := argon2.IDKey([]byte(password), hash.salt, hash.iterations, hash.memory, hash.parallelism, uint32(len(hash.key)))
+
+	return subtle.ConstantTimeCompare(hash.key, key) == 1, nil
+}
+
+func (hasher *Argon2idHasher) NeedsRehash(encodedHash string) bool {
+	hash, err := decodeArgon2idHash(encodedHash)
+	if err != nil {
+		return true
+	}
+	return hash.memory != hasher.Memory ||
+		hash.iterations != hasher.Iterations ||
+		hash.parallelism != hasher.Parallelism ||
+		uint32(len(hash.salt)) != hasher.SaltLength ||
+		uint32(len(hash.key)) != hasher.KeyLength
+}
+
+func decodeArgon2idHash(encodedHash string) (argon2idHash, error) {
+	hash := argon2idHash{}
+
+	// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>
+	values := strings.Split(encodedHash, "$")
+	if len(values) != 6 || values[1] != string(Argon2id) {
+		return hash, ErrInvalidHash
+	}
+
+	if _, err := fmt.Sscanf(values[2], "v=%d", &hash.version); err != nil {
+		return hash, ErrInvalidHash
+	}
+	if hash.version != argon2.Version {
+		return hash, ErrIncompatibleVersion
+	}
+
+	if _, err := fmt.Sscanf(values[3], "m=%d,t=%d,p=%d", &hash.memory, &hash.iterations, &hash.parallelism); err != nil {
+		return hash, ErrInvalidHash
+	}
+	// argon2.IDKey panics on zero iterations or parallelism
+	if hash.memory < 1 || hash.iterations < 1 || hash.parallelism < 1 {
+		return hash, ErrInvalidHash
+	}
+
+	var err error
+	if hash.salt, err = base64.RawStdEncoding.DecodeString(values[4]); err != nil || len(hash.salt) == 0 {
+		return hash, ErrInvalidHash
+	}
+	// An empty key would match any password
+	if hash.key, err = base64.RawStdEncoding.DecodeString(values[5]); err != nil || len(hash.key) == 0 {
+		return hash, ErrInvalidHash
+	}
+
+	return hash, nil
+}
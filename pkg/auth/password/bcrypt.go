@@ -0,0 +1,50 @@
+package password
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type BcryptHasher struct {
+	Cost int
+}
+
+func NewBcryptHasher() *BcryptHasher {
+	return &BcryptHasher{
+		Cost: 12,
+	}
+}
+
+func (hasher *BcryptHasher) Algorithm() Algorithm {
+	return Bcrypt
+}
+
+// Hash returns the bcrypt modular crypt format ($2a$<cost>$<salt><hash>), which is the PHC representation of bcrypt
+func (hasher *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), hasher.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Verify relies on bcrypt.CompareHashAndPassword, which compares the hashes in constant time
+func (hasher *BcryptHasher) Verify(password string, encodedHash string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, ErrInvalidHash
+	}
+	return true, nil
+}
+
+func (hasher *BcryptHasher) NeedsRehash(encodedHash string) bool {
+	cost, err := bcrypt.Cost([]byte(encodedHash))
+	if err != nil {
+		return true
+	}
+	return cost != hasher.Cost
+}
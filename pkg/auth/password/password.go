@@ -0,0 +1,95 @@
+package password
+
+import (
+	"errors"
+	"strings"
+)
+
+type Algorithm string
+
+const (
+	Argon2id Algorithm = "argon2id"
+	Bcrypt   Algorithm = "bcrypt"
+)
+
+var (
+	ErrInvalidHash          = errors.New("the encoded hash is not in the correct format")
+	ErrIncompatibleVersion  = errors.New("incompatible version of the hashing algorithm")
+	ErrUnsupportedAlgorithm = errors.New("unsupported hashing algorithm")
+)
+
+// IHasher hashes passwords into PHC formatted strings ($<id>$<params>$<salt>$<hash>)
+type IHasher interface {
+	Algorithm() Algorithm
+	Hash(password string) (string, error)
+	// Verify compares the password against the encoded hash in constant time
+	Verify(password string, encodedHash string) (bool, error)
+	// NeedsRehash reports if the encoded hash was generated with parameters different from the current ones
+	NeedsRehash(encodedHash string) bool
+}
+
+// Manager hashes new passwords with the current hasher and verifies hashes created by any registered hasher,
+// so the algorithm or its parameters can be changed without invalidating the stored credentials
+type Manager struct {
+	current IHasher
+	hashers map[Algorithm]IHasher
+}
+
+func NewManager(current IHasher, legacy ...IHasher) *Manager {
+	manager := &Manager{
+		current: current,
+		hashers: make(map[Algorithm]IHasher),
+	}
+	for _, hasher := range legacy {
+		manager.hashers[hasher.Algorithm()] = hasher
+	}
+	manager.hashers[current.Algorithm()] = current
+	return manager
+}
+
+// NewDefaultManager hashes with argon2id and still accepts bcrypt hashes
+func NewDefaultManager() *Manager {
+	return NewManager(NewArgon2idHasher(), NewBcryptHasher())
+}
+
+func (manager *Manager) Hash(password string) (string, error) {
+	return manager.current.Hash(password)
+}
+
+// Verify checks the password against the encoded hash. When the password matches but the hash was created
+// with another algorithm or outdated parameters, a new hash is returned so the caller can store it
+func (manager *Manager) Verify(password string, encodedHash string) (bool, string, error) {
+	algorithm, err := identify(encodedHash)
+	if err != nil {
+		return false, "", err
+	}
+
+	hasher, ok := manager.hashers[algorithm]
+	if !ok {
+		return false, "", ErrUnsupportedAlgorithm
+	}
+
+	match, err := hasher.Verify(password, encodedHash)
+	if err != nil || !match {
+		return false, "", err
+	}
+
+	if algorithm != manager.current.Algorithm() || manager.current.NeedsRehash(encodedHash) {
+		newHash, err := manager.current.Hash(password)
+		if err != nil {
+			return true, "", err
+		}
+		return true, newHash, nil
+	}
+	return true, "", nil
+}
+
+func identify(encodedHash string) (Algorithm, error) {
+	switch {
+	case strings.HasPrefix(encodedHash, "$argon2id$"):
+		return Argon2id, nil
+	case strings.HasPrefix(encodedHash, "$2a$"), strings.HasPrefix(encodedHash, "$2b$"), strings.HasPrefix(encodedHash, "$2y$"):
+		return Bcrypt, nil
+	}
+	return "", ErrUnsupportedAlgorithm
+}
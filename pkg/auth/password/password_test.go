@@ -0,0 +1,97 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestArgon2idHasher() *Argon2idHasher {
+	hasher := NewArgon2idHasher()
+	hasher.Memory = 1024
+	hasher.Iterations = 1
+	return hasher
+}
+
+func TestArgon2idHasher(t *testing.T) {
+	hasher := newTestArgon2idHasher()
+
+	hash, err := hasher.Hash("my-password")
+	assert.Nil(t, err)
+	assert.Regexp(t, `^\$argon2id\$v=19\$m=1024,t=1,p=2\$[A-Za-z0-9+/]+\$[A-Za-z0-9+/]+$`, hash)
+
+	match, err := hasher.Verify("my-password", hash)
+	assert.Nil(t, err)
+	assert.True(t, match)
+
+	match, err = hasher.Verify("wrong-password", hash)
+	assert.Nil(t, err)
+	assert.False(t, match)
+
+	assert.False(t, hasher.NeedsRehash(hash))
+	hasher.Iterations = 2
+	assert.True(t, hasher.NeedsRehash(hash))
+
+	_, err = hasher.Verify("my-password", "$argon2id$v=19$invalid")
+	assert.Equal(t, ErrInvalidHash, err)
+}
+
+func TestArgon2idHasherInvalidHashes(t *testing.T) {
+	hasher := newTestArgon2idHasher()
+
+	invalidHashes := []string{
+		"$argon2id$v=19$m=1024,t=1,p=1$c2FsdA$",
+		"$argon2id$v=19$m=1024,t=1,p=1$$a2V5",
+		"$argon2id$v=19$m=1024,t=0,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=1024,t=1,p=0$c2FsdA$a2V5",
+		"$argon2id$v=19$m=0,t=1,p=1$c2FsdA$a2V5",
+	}
+	for _, invalidHash := range invalidHashes {
+		match, err := hasher.Verify("any-password", invalidHash)
+		assert.Equal(t, ErrInvalidHash, err, invalidHash)
+		assert.False(t, match, invalidHash)
+		assert.True(t, hasher.NeedsRehash(invalidHash), invalidHash)
+	}
+}
+
+func TestBcryptHasher(t *testing.T) {
+	hasher := &BcryptHasher{Cost: 4}
+
+	hash, err := hasher.Hash("my-password")
+	assert.Nil(t, err)
+
+	match, err := hasher.Verify("my-password", hash)
+	assert.Nil(t, err)
+	assert.True(t, match)
+
+	match, err = hasher.Verify("wrong-password", hash)
+	assert.Nil(t, err)
+	assert.False(t, match)
+
+	assert.True(t, (&BcryptHasher{Cost: 5}).NeedsRehash(hash))
+}
+
+func TestManagerRehashOnLogin(t *testing.T) {
+	bcryptHasher := &BcryptHasher{Cost: 4}
+	manager := NewManager(newTestArgon2idHasher(), bcryptHasher)
+
+	legacyHash, _ := bcryptHasher.Hash("my-password")
+
+	match, newHash, err := manager.Verify("my-password", legacyHash)
+	assert.Nil(t, err)
+	assert.True(t, match)
+	assert.Regexp(t, `^\$argon2id\$`, newHash)
+
+	match, rehash, err := manager.Verify("my-password", newHash)
+	assert.Nil(t, err)
+	assert.True(t, match)
+	assert.Empty(t, rehash)
+
+	match, rehash, err = manager.Verify("wrong-password", legacyHash)
+	assert.Nil(t, err)
+	assert.False(t, match)
+	assert.Empty(t, rehash)
+
+	_, _, err = manager.Verify("my-password", "$scrypt$ln=16,r=8,p=1$salt$hash")
+	assert.Equal(t, ErrUnsupportedAlgorithm, err)
+}
@@ -8,15 +8,29 @@ import (
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tenant"
 	"github.com/marcelofelixsalgado/financial-commons/settings"
 )
 
+// Authentication level claim. It is true when the user completed the multi-factor authentication
+const mfaClaim = "mfa"
+
 func CreateToken(userId string, tenantId string) (string, error) {
+	return createUserToken(userId, tenantId, false)
+}
+
+// CreateMFAToken creates a token for users who completed the multi-factor authentication
+func CreateMFAToken(userId string, tenantId string) (string, error) {
+	return createUserToken(userId, tenantId, true)
+}
+
+func createUserToken(userId string, tenantId string, mfa bool) (string, error) {
 	permissions := jwt.MapClaims{}
 	permissions["Authorized"] = true
 	permissions["exp"] = time.Now().Add(time.Hour * 6).Unix()
 	permissions["userId"] = userId
-	permissions["tenantId"] = tenantId
+	permissions[tenant.ClaimName] = tenantId
+	permissions[mfaClaim] = mfa
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, permissions)
 	jwtToken, err := token.SignedString(settings.Config.SecretKey)
 	if err != nil {
@@ -26,7 +40,10 @@ func CreateToken(userId string, tenantId string) (string, error) {
 }
 
 func ValidateToken(r *http.Request) error {
-	tokenString := extractToken(r)
+	tokenString, err := ExtractToken(r)
+	if err != nil {
+		return err
+	}
 	token, err := jwt.Parse(tokenString, getVerificationKey)
 	if err != nil {
 		return err
@@ -39,16 +56,6 @@ func ValidateToken(r *http.Request) error {
 	return errors.New("invalid token")
 }
 
-func extractToken(r *http.Request) string {
-	token := r.Header.Get("Authorization")
-
-	// Bearer 123
-	if len(strings.Split(token, " ")) == 2 {
-		return strings.Split(token, " ")[1]
-	}
-	return ""
-}
-
 func getVerificationKey(token *jwt.Token) (interface{}, error) {
 	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 		return nil, fmt.Errorf("unexpected signature method! %v", token.Header["alg"])
@@ -57,21 +64,71 @@ func getVerificationKey(token *jwt.Token) (interface{}, error) {
 }
 
 func extractClaims(claim string, r *http.Request) (string, error) {
-	tokenString := extractToken(r)
+	tokenString, err := ExtractToken(r)
+	if err != nil {
+		return "", err
+	}
 	token, err := jwt.Parse(tokenString, getVerificationKey)
 	if err != nil {
 		return "", err
 	}
 	if permissions, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return permissions[claim].(string), nil
+		value, ok := permissions[claim].(string)
+		if !ok {
+			return "", fmt.Errorf("claim not found: %s", claim)
+		}
+		return value, nil
 	}
 	return "", errors.New("ivalid token")
 }
 
+// ExtractIdentity validates the bearer token sent on the Authorization header and returns the user or service identity
+// it belongs to
+func ExtractIdentity(r *http.Request) (Identity, error) {
+	return ExtractIdentityWithExtractor(r, NewHeaderExtractor())
+}
+
+// ExtractIdentityWithExtractor is ExtractIdentity for the tokens read by the extractor
+func ExtractIdentityWithExtractor(r *http.Request, extractor ITokenExtractor) (Identity, error) {
+	tokenString, err := extractor.Extract(r)
+	if err != nil {
+		return Identity{}, err
+	}
+	token, err := jwt.Parse(tokenString, getVerificationKey)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	permissions, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return Identity{}, errors.New("invalid token")
+	}
+
+	identity := Identity{
+		Type:        UserIdentity,
+		Scheme:      BearerScheme,
+		accessToken: tokenString,
+	}
+	identity.TenantId, _ = permissions[tenant.ClaimName].(string)
+
+	if clientId, ok := permissions[clientIdClaim].(string); ok {
+		identity.Type = ServiceIdentity
+		identity.Subject = clientId
+		if scope, ok := permissions[scopeClaim].(string); ok {
+			identity.Scopes = strings.Fields(scope)
+		}
+		return identity, nil
+	}
+
+	identity.Subject, _ = permissions["userId"].(string)
+	identity.MFA, _ = permissions[mfaClaim].(bool)
+	return identity, nil
+}
+
 func ExtractUserId(r *http.Request) (string, error) {
 	return extractClaims("userId", r)
 }
 
 func ExtractTenantId(r *http.Request) (string, error) {
-	return extractClaims("tenantId", r)
+	return extractClaims(tenant.ClaimName, r)
 }
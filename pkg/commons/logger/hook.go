@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"github.com/marcelofelixsalgado/financial-commons/pkg/correlation"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tracing"
+	"github.com/sirupsen/logrus"
+)
+
+// contextHook adds the request and correlation ids and the trace context to the entries created with a context
+type contextHook struct{}
+
+func (hook *contextHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (hook *contextHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	if ids, ok := correlation.FromContext(entry.Context); ok {
+		entry.Data["request_id"] = ids.RequestID
+		entry.Data["correlation_id"] = ids.CorrelationID
+	}
+	if spanContext := tracing.SpanContextFromContext(entry.Context); spanContext.IsValid() {
+		entry.Data["trace_id"] = spanContext.TraceID.String()
+		entry.Data["span_id"] = spanContext.SpanID.String()
+	}
+	return nil
+}
@@ -1,10 +1,12 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/masking"
 	"github.com/marcelofelixsalgado/financial-commons/settings"
 
 	"github.com/labstack/echo/v4"
@@ -24,7 +26,13 @@ var (
 	hostname string
 )
 
-// GetLogger - Returns an instance of logger
+// GetLoggerWithContext - Returns an instance of logger which adds the values carried by the context (request and correlation ids)
+func GetLoggerWithContext(ctx context.Context) *logrus.Entry {
+	return GetLogger().WithContext(ctx)
+}
+
+// GetLogger - Returns an instance of logger. The entries only carry the request and correlation ids when created by
+// GetLoggerWithContext, which must be used on the request path
 func GetLogger() *logrus.Entry {
 	if entry == nil {
 		initLogger()
@@ -48,6 +56,8 @@ func initLogger() {
 	entry.SetFormatter(&logrus.JSONFormatter{})
 	entry.SetOutput(getLogFile())
 	entry.SetLevel(logLevel)
+	entry.AddHook(&contextHook{})
+	entry.AddHook(masking.NewHook(masking.Default()))
 }
 
 func getLogFile() *os.File {
@@ -0,0 +1,34 @@
+package masking
+
+import "github.com/sirupsen/logrus"
+
+// Hook masks the message and the fields of the logrus entries
+type Hook struct {
+	Masker *Masker
+}
+
+func NewHook(masker *Masker) *Hook {
+	return &Hook{Masker: masker}
+}
+
+func (hook *Hook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (hook *Hook) Fire(entry *logrus.Entry) error {
+	entry.Message = hook.Masker.MaskString(entry.Message)
+
+	for key, value := range entry.Data {
+		if hook.Masker.IsSensitiveField(key) {
+			entry.Data[key] = MaskedValue
+			continue
+		}
+		switch typedValue := value.(type) {
+		case string:
+			entry.Data[key] = hook.Masker.MaskString(typedValue)
+		case error:
+			entry.Data[key] = hook.Masker.MaskString(typedValue.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package masking
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const MaskedValue = "[REDACTED]"
+
+// DefaultFieldNames are the field names (JSON keys, query parameters and log fields) which are always masked. Names
+// are matched ignoring case, underscores and hyphens, and a field is masked when its name contains any of them
+var DefaultFieldNames = []string{
+	"password",
+	"token",
+	"secret",
+	"authorization",
+	"cookie",
+	"accountNumber",
+	"cardNumber",
+	"cvv",
+	"apiKey",
+}
+
+// DefaultHeaders are the header names which are always masked
+var DefaultHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-API-Key",
+	"X-CSRF-Token",
+}
+
+// Masker masks sensitive data using rule sets for field names, JSON paths, header names and value patterns
+type Masker struct {
+	fieldNames []string
+	jsonPaths  [][]string
+	headers    map[string]bool
+	patterns   []Pattern
+}
+
+type Option func(*Masker)
+
+func New(options ...Option) *Masker {
+	masker := &Masker{
+		headers: make(map[string]bool),
+	}
+	for _, option := range options {
+		option(masker)
+	}
+	return masker
+}
+
+// Default returns a masker with the default field names and headers and all the built-in patterns
+func Default() *Masker {
+	return New(
+		WithFieldNames(DefaultFieldNames...),
+		WithHeaders(DefaultHeaders...),
+		WithPatterns(BuiltinPatterns()...),
+	)
+}
+
+// WithFieldNames masks the fields whose names contain any of the names
+func WithFieldNames(names ...string) Option {
+	return func(masker *Masker) {
+		for _, name := range names {
+			masker.fieldNames = append(masker.fieldNames, normalizeFieldName(name))
+		}
+	}
+}
+
+// WithJSONPaths masks the JSON values on the paths. Paths are dot separated ("$." prefix is optional) and "*" matches
+// any object key. Array items are always matched by "*" (e.g. "$.account.number", "cards.*.holder")
+func WithJSONPaths(paths ...string) Option {
+	return func(masker *Masker) {
+		for _, path := range paths {
+			path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+			masker.jsonPaths = append(masker.jsonPaths, strings.Split(path, "."))
+		}
+	}
+}
+
+// WithHeaders masks the headers (case insensitive)
+func WithHeaders(names ...string) Option {
+	return func(masker *Masker) {
+		for _, name := range names {
+			masker.headers[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// WithPatterns masks the parts of the string values matching the patterns
+func WithPatterns(patterns ...Pattern) Option {
+	return func(masker *Masker) {
+		masker.patterns = append(masker.patterns, patterns...)
+	}
+}
+
+// MaskString masks the parts of the text which match the patterns
+func (masker *Masker) MaskString(text string) string {
+	for _, pattern := range masker.patterns {
+		text = pattern.apply(text)
+	}
+	return text
+}
+
+// MaskJSON masks a JSON document. Documents which are not valid JSON are masked as plain text
+func (masker *Masker) MaskJSON(document string) string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(document), &value); err != nil {
+		return masker.MaskString(document)
+	}
+
+	masked, err := json.Marshal(masker.maskValue(value, nil))
+	if err != nil {
+		return masker.MaskString(document)
+	}
+	return string(masked)
+}
+
+// MaskHeaders returns a copy of the headers with the sensitive ones masked
+func (masker *Masker) MaskHeaders(header http.Header) http.Header {
+	masked := make(http.Header, len(header))
+	for name, values := range header {
+		if masker.headers[http.CanonicalHeaderKey(name)] || masker.IsSensitiveField(name) {
+			masked[name] = []string{MaskedValue}
+			continue
+		}
+		maskedValues := make([]string, 0, len(values))
+		for _, value := range values {
+			maskedValues = append(maskedValues, masker.MaskString(value))
+		}
+		masked[name] = maskedValues
+	}
+	return masked
+}
+
+// MaskQuery returns a copy of the query parameters with the sensitive ones masked
+func (masker *Masker) MaskQuery(query url.Values) url.Values {
+	masked := make(url.Values, len(query))
+	for name, values := range query {
+		if masker.IsSensitiveField(name) {
+			masked[name] = []string{MaskedValue}
+			continue
+		}
+		maskedValues := make([]string, 0, len(values))
+		for _, value := range values {
+			maskedValues = append(maskedValues, masker.MaskString(value))
+		}
+		masked[name] = maskedValues
+	}
+	return masked
+}
+
+// MaskURI masks the query parameters of the URI
+func (masker *Masker) MaskURI(uri string) string {
+	parsedURI, err := url.ParseRequestURI(uri)
+	if err != nil || parsedURI.RawQuery == "" {
+		return uri
+	}
+	parsedURI.RawQuery = masker.MaskQuery(parsedURI.Query()).Encode()
+	return parsedURI.String()
+}
+
+// MaskValue masks a decoded value (maps, slices and strings). Maps and slices are masked in place
+func (masker *Masker) MaskValue(value interface{}) interface{} {
+	return masker.maskValue(value, nil)
+}
+
+// IsSensitiveField checks the field name against the field name rules
+func (masker *Masker) IsSensitiveField(name string) bool {
+	normalizedName := normalizeFieldName(name)
+	for _, fieldName := range masker.fieldNames {
+		if strings.Contains(normalizedName, fieldName) {
+			return true
+		}
+	}
+	return false
+}
+
+func (masker *Masker) maskValue(value interface{}, path []string) interface{} {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typedValue {
+			childPath := append(path[:len(path):len(path)], key)
+			if masker.IsSensitiveField(key) || masker.matchesJSONPath(childPath) {
+				typedValue[key] = MaskedValue
+			} else {
+				typedValue[key] = masker.maskValue(child, childPath)
+			}
+		}
+	case []interface{}:
+		for i, child := range typedValue {
+			childPath := append(path[:len(path):len(path)], "*")
+			if masker.matchesJSONPath(childPath) {
+				typedValue[i] = MaskedValue
+			} else {
+				typedValue[i] = masker.maskValue(child, childPath)
+			}
+		}
+	case string:
+		return masker.MaskString(typedValue)
+	}
+	return value
+}
+
+func (masker *Masker) matchesJSONPath(path []string) bool {
+	for _, jsonPath := range masker.jsonPaths {
+		if len(jsonPath) != len(path) {
+			continue
+		}
+		matches := true
+		for i, segment := range jsonPath {
+			if segment != "*" && segment != path[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
@@ -0,0 +1,108 @@
+package masking
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPAN(t *testing.T) {
+	masker := New(WithPatterns(PAN()))
+
+	assert.Equal(t, "card ************1111 approved", masker.MaskString("card 4111111111111111 approved"))
+	assert.Equal(t, "**** **** **** 1111", masker.MaskString("4111 1111 1111 1111"))
+	assert.Equal(t, "****-****-****-4444", masker.MaskString("5555-5555-5555-4444"))
+	// Luhn invalid
+	assert.Equal(t, "4111111111111112", masker.MaskString("4111111111111112"))
+}
+
+func TestCPF(t *testing.T) {
+	masker := New(WithPatterns(CPF()))
+
+	assert.Equal(t, "***.***.***-25", masker.MaskString("529.982.247-25"))
+	assert.Equal(t, "cpf *********25", masker.MaskString("cpf 52998224725"))
+	// Invalid check digits
+	assert.Equal(t, "529.982.247-26", masker.MaskString("529.982.247-26"))
+	assert.Equal(t, "111.111.111-11", masker.MaskString("111.111.111-11"))
+}
+
+func TestCNPJ(t *testing.T) {
+	masker := New(WithPatterns(CNPJ()))
+
+	assert.Equal(t, "**.***.***/****-81", masker.MaskString("11.222.333/0001-81"))
+	assert.Equal(t, "************81", masker.MaskString("11222333000181"))
+	// Invalid check digits
+	assert.Equal(t, "11.222.333/0001-82", masker.MaskString("11.222.333/0001-82"))
+}
+
+func TestIBAN(t *testing.T) {
+	masker := New(WithPatterns(IBAN()))
+
+	assert.Equal(t, "DE****************3000", masker.MaskString("DE89370400440532013000"))
+	assert.Equal(t, "GB** **** **** **** **54 32", masker.MaskString("GB82 WEST 1234 5698 7654 32"))
+	// Invalid checksum
+	assert.Equal(t, "DE89370400440532013001", masker.MaskString("DE89370400440532013001"))
+}
+
+func TestEmail(t *testing.T) {
+	masker := New(WithPatterns(Email()))
+
+	assert.Equal(t, "contact j***@example.com.br", masker.MaskString("contact john.doe+bank@example.com.br"))
+	assert.Equal(t, "no email here", masker.MaskString("no email here"))
+}
+
+func TestBuiltinPatternsOrder(t *testing.T) {
+	masker := New(WithPatterns(BuiltinPatterns()...))
+
+	// Card numbers with 14 digits are masked as cards, not as taxpayer numbers
+	assert.Equal(t, "**********5904", masker.MaskString("30569309025904"))
+	assert.Equal(t, "***.***.***-25", masker.MaskString("529.982.247-25"))
+}
+
+func TestMaskJSON(t *testing.T) {
+	masker := New(
+		WithFieldNames("password"),
+		WithJSONPaths("$.account.number", "beneficiaries.*.document"),
+		WithPatterns(Email()),
+	)
+
+	masked := masker.MaskJSON(`{"user":"john@example.com","user_password":"123","account":{"number":"0001","bank":"001"},"number":"1","beneficiaries":[{"document":"52998224725","name":"Mary"}]}`)
+
+	assert.JSONEq(t, `{"user":"j***@example.com","user_password":"[REDACTED]","account":{"number":"[REDACTED]","bank":"001"},"number":"1","beneficiaries":[{"document":"[REDACTED]","name":"Mary"}]}`, masked)
+	assert.Equal(t, "plain j***@example.com", masker.MaskJSON("plain john@example.com"))
+}
+
+func TestMaskHeaders(t *testing.T) {
+	masker := Default()
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer 123")
+	header.Set("X-Api-Key", "fk_123")
+	header.Set("Content-Type", "application/json")
+
+	masked := masker.MaskHeaders(header)
+
+	assert.Equal(t, []string{MaskedValue}, masked["Authorization"])
+	assert.Equal(t, []string{MaskedValue}, masked["X-Api-Key"])
+	assert.Equal(t, []string{"application/json"}, masked["Content-Type"])
+	assert.Equal(t, "Bearer 123", header.Get("Authorization"))
+}
+
+func TestHook(t *testing.T) {
+	hook := NewHook(Default())
+
+	entry := logrus.NewEntry(logrus.New()).WithFields(logrus.Fields{
+		"password": "123456",
+		"document": "529.982.247-25",
+		"attempts": 3,
+	})
+	entry.Message = "Login failed for john@example.com"
+
+	assert.Nil(t, hook.Fire(entry))
+	assert.Equal(t, "Login failed for j***@example.com", entry.Message)
+	assert.Equal(t, MaskedValue, entry.Data["password"])
+	assert.Equal(t, "***.***.***-25", entry.Data["document"])
+	assert.Equal(t, 3, entry.Data["attempts"])
+}
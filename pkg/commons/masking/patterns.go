@@ -0,0 +1,206 @@
+package masking
+
+import (
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Pattern masks the parts of a text matching the expression. Matches rejected by Validate are kept
+type Pattern struct {
+	Name     string
+	Regexp   *regexp.Regexp
+	Validate func(match string) bool
+	Mask     func(match string) string
+}
+
+var (
+	panRegexp   = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	cpfRegexp   = regexp.MustCompile(`\b\d{3}\.\d{3}\.\d{3}-\d{2}\b|\b\d{11}\b`)
+	cnpjRegexp  = regexp.MustCompile(`\b\d{2}\.\d{3}\.\d{3}/\d{4}-\d{2}\b|\b\d{14}\b`)
+	ibanRegexp  = regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`)
+	emailRegexp = regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)
+)
+
+// PAN masks payment card numbers (Luhn valid), keeping the last 4 digits
+func PAN() Pattern {
+	return Pattern{
+		Name:     "pan",
+		Regexp:   panRegexp,
+		Validate: func(match string) bool { return isLuhnValid(onlyDigits(match)) },
+		Mask:     func(match string) string { return maskDigits(match, 4) },
+	}
+}
+
+// CPF masks brazilian individual taxpayer numbers (check digits valid), keeping the last 2 digits
+func CPF() Pattern {
+	return Pattern{
+		Name:     "cpf",
+		Regexp:   cpfRegexp,
+		Validate: func(match string) bool { return isCPFValid(onlyDigits(match)) },
+		Mask:     func(match string) string { return maskDigits(match, 2) },
+	}
+}
+
+// CNPJ masks brazilian company taxpayer numbers (check digits valid), keeping the last 2 digits
+func CNPJ() Pattern {
+	return Pattern{
+		Name:     "cnpj",
+		Regexp:   cnpjRegexp,
+		Validate: func(match string) bool { return isCNPJValid(onlyDigits(match)) },
+		Mask:     func(match string) string { return maskDigits(match, 2) },
+	}
+}
+
+// IBAN masks international bank account numbers (mod 97 valid), keeping the country code and the last 4 characters
+func IBAN() Pattern {
+	return Pattern{
+		Name:     "iban",
+		Regexp:   ibanRegexp,
+		Validate: isIBANValid,
+		Mask: func(match string) string {
+			characters := []rune(match)
+			keep := 4
+			for i := len(characters) - 1; i >= 2; i-- {
+				if characters[i] == ' ' {
+					continue
+				}
+				if keep > 0 {
+					keep--
+					continue
+				}
+				characters[i] = '*'
+			}
+			return string(characters)
+		},
+	}
+}
+
+// Email masks the local part of email addresses, keeping its first character
+func Email() Pattern {
+	return Pattern{
+		Name:   "email",
+		Regexp: emailRegexp,
+		Mask: func(match string) string {
+			at := strings.LastIndex(match, "@")
+			return match[:1] + "***" + match[at:]
+		},
+	}
+}
+
+// BuiltinPatterns returns all the built-in patterns. Card numbers are checked first, since their digits could also
+// form a valid taxpayer number
+func BuiltinPatterns() []Pattern {
+	return []Pattern{PAN(), CNPJ(), CPF(), IBAN(), Email()}
+}
+
+func (pattern Pattern) apply(text string) string {
+	return pattern.Regexp.ReplaceAllStringFunc(text, func(match string) string {
+		if pattern.Validate != nil && !pattern.Validate(match) {
+			return match
+		}
+		return pattern.Mask(match)
+	})
+}
+
+// maskDigits replaces the digits by "*", except the last ones. Separators are kept
+func maskDigits(text string, keep int) string {
+	characters := []rune(text)
+	for i := len(characters) - 1; i >= 0; i-- {
+		if characters[i] < '0' || characters[i] > '9' {
+			continue
+		}
+		if keep > 0 {
+			keep--
+			continue
+		}
+		characters[i] = '*'
+	}
+	return string(characters)
+}
+
+func onlyDigits(text string) string {
+	var digits strings.Builder
+	for _, character := range text {
+		if character >= '0' && character <= '9' {
+			digits.WriteRune(character)
+		}
+	}
+	return digits.String()
+}
+
+func isLuhnValid(digits string) bool {
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := int(digits[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+func isCPFValid(digits string) bool {
+	if len(digits) != 11 || allSameDigit(digits) {
+		return false
+	}
+	return checkDigit(digits[:9], []int{10, 9, 8, 7, 6, 5, 4, 3, 2}) == int(digits[9]-'0') &&
+		checkDigit(digits[:10], []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2}) == int(digits[10]-'0')
+}
+
+func isCNPJValid(digits string) bool {
+	if len(digits) != 14 || allSameDigit(digits) {
+		return false
+	}
+	return checkDigit(digits[:12], []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == int(digits[12]-'0') &&
+		checkDigit(digits[:13], []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == int(digits[13]-'0')
+}
+
+// checkDigit calculates the mod 11 check digit used by CPF and CNPJ
+func checkDigit(digits string, weights []int) int {
+	sum := 0
+	for i, weight := range weights {
+		sum += int(digits[i]-'0') * weight
+	}
+	remainder := sum % 11
+	if remainder < 2 {
+		return 0
+	}
+	return 11 - remainder
+}
+
+func allSameDigit(digits string) bool {
+	return strings.Count(digits, digits[:1]) == len(digits)
+}
+
+// isIBANValid checks the ISO 13616 mod 97 checksum
+func isIBANValid(match string) bool {
+	iban := strings.ReplaceAll(match, " ", "")
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	var numeric strings.Builder
+	for _, character := range iban[4:] + iban[:4] {
+		if character >= 'A' && character <= 'Z' {
+			numeric.WriteString(big.NewInt(int64(character - 'A' + 10)).String())
+		} else {
+			numeric.WriteRune(character)
+		}
+	}
+
+	value, ok := new(big.Int).SetString(numeric.String(), 10)
+	if !ok {
+		return false
+	}
+	return new(big.Int).Mod(value, big.NewInt(97)).Int64() == 1
+}
@@ -0,0 +1,47 @@
+package correlation
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	RequestIDHeader     = "X-Request-ID"
+	CorrelationIDHeader = "X-Correlation-ID"
+)
+
+// IDs identify a request (RequestID) and the whole flow it belongs to across services (CorrelationID)
+type IDs struct {
+	RequestID     string
+	CorrelationID string
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of the parent context carrying the ids
+func NewContext(parent context.Context, ids IDs) context.Context {
+	return context.WithValue(parent, contextKey{}, ids)
+}
+
+// FromContext returns the ids stored in the context, if any
+func FromContext(ctx context.Context) (IDs, bool) {
+	if ctx == nil {
+		return IDs{}, false
+	}
+	ids, ok := ctx.Value(contextKey{}).(IDs)
+	return ids, ok
+}
+
+// SetHeaders copies the ids stored in the context to the headers
+func SetHeaders(ctx context.Context, header http.Header) {
+	ids, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+	if ids.RequestID != "" {
+		header.Set(RequestIDHeader, ids.RequestID)
+	}
+	if ids.CorrelationID != "" {
+		header.Set(CorrelationIDHeader, ids.CorrelationID)
+	}
+}
@@ -0,0 +1,38 @@
+package deadline
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Header carries the time (in milliseconds) the caller is still willing to wait for the response. A relative value is
+// used instead of an absolute deadline, so it does not depend on the clocks of the services being in sync
+const Header = "X-Request-Timeout"
+
+// SetHeader sets the time remaining until the context deadline, if it has one
+func SetHeader(ctx context.Context, header http.Header) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	remaining := time.Until(deadline).Milliseconds()
+	if remaining < 0 {
+		remaining = 0
+	}
+	header.Set(Header, strconv.FormatInt(remaining, 10))
+}
+
+// FromHeader returns the time remaining set by the caller. Invalid or negative values are ignored
+func FromHeader(header http.Header) (time.Duration, bool) {
+	value := header.Get(Header)
+	if value == "" {
+		return 0, false
+	}
+	milliseconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || milliseconds < 0 {
+		return 0, false
+	}
+	return time.Duration(milliseconds) * time.Millisecond, true
+}
@@ -0,0 +1,46 @@
+package deadline
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetHeader(t *testing.T) {
+	header := http.Header{}
+	SetHeader(context.Background(), header)
+	assert.Empty(t, header.Get(Header))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	SetHeader(ctx, header)
+
+	remaining, ok := FromHeader(header)
+	assert.True(t, ok)
+	assert.True(t, remaining > time.Second && remaining <= 2*time.Second)
+}
+
+func TestFromHeader(t *testing.T) {
+	tests := []struct {
+		value     string
+		remaining time.Duration
+		ok        bool
+	}{
+		{"", 0, false},
+		{"1500", 1500 * time.Millisecond, true},
+		{"0", 0, true},
+		{"-1", 0, false},
+		{"abc", 0, false},
+	}
+
+	for _, test := range tests {
+		header := http.Header{}
+		header.Set(Header, test.value)
+		remaining, ok := FromHeader(header)
+		assert.Equal(t, test.ok, ok, test.value)
+		assert.Equal(t, test.remaining, remaining, test.value)
+	}
+}
@@ -1,14 +1,18 @@
 package events
 
 import (
+	"context"
 	"errors"
 	"sync"
+
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tenant"
 )
 
 type IEventDispatcher interface {
 	Register(eventName string, handler IEventHandler) error
 	Has(eventName string, handler IEventHandler) bool
 	Dispatch(event IEvent) error
+	DispatchWithContext(ctx context.Context, event IEvent) error
 	Unregister(eventName string, handler IEventHandler) error
 	UnregisterAll()
 }
@@ -62,6 +66,16 @@ func (ev *EventDispatcher) Dispatch(event IEvent) error {
 	return nil
 }
 
+// DispatchWithContext stamps tenant events with the tenant carried by the context before dispatching them
+func (ev *EventDispatcher) DispatchWithContext(ctx context.Context, event IEvent) error {
+	if tenantEvent, ok := event.(ITenantEvent); ok && tenantEvent.GetTenantId() == "" {
+		if tenantId, ok := tenant.FromContext(ctx); ok {
+			tenantEvent.SetTenantId(tenantId)
+		}
+	}
+	return ev.Dispatch(event)
+}
+
 func (ed *EventDispatcher) Unregister(eventName string, handler IEventHandler) error {
 	if _, ok := ed.handlers[eventName]; ok {
 		for i, h := range ed.handlers[eventName] {
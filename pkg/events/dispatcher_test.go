@@ -1,10 +1,12 @@
 package events
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tenant"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -31,6 +33,19 @@ func (e *TestEvent) SetPayload(payload interface{}) {
 	e.Payload = payload
 }
 
+type TestTenantEvent struct {
+	TestEvent
+	TenantId string
+}
+
+func (e *TestTenantEvent) GetTenantId() string {
+	return e.TenantId
+}
+
+func (e *TestTenantEvent) SetTenantId(tenantId string) {
+	e.TenantId = tenantId
+}
+
 type TestEventHandler struct {
 	ID int
 }
@@ -167,6 +182,19 @@ func (suite *EventDispatcherTestSuite) TestEventDispatch_Dispatch() {
 	eh2.AssertNumberOfCalls(suite.T(), "Handle", 1)
 }
 
+func (suite *EventDispatcherTestSuite) TestEventDispatch_DispatchWithContext() {
+	event := &TestTenantEvent{TestEvent: TestEvent{Name: "test1", Payload: "test1"}}
+
+	eh := &MockHandler{}
+	eh.On("Handle", event)
+	suite.eventDispatcher.Register(event.GetName(), eh)
+
+	err := suite.eventDispatcher.DispatchWithContext(tenant.NewContext(context.Background(), "tenant-1"), event)
+	suite.Nil(err)
+	suite.Equal("tenant-1", event.GetTenantId())
+	eh.AssertNumberOfCalls(suite.T(), "Handle", 1)
+}
+
 func TestSuite(t *testing.T) {
 	suite.Run(t, new(EventDispatcherTestSuite))
 }
@@ -8,3 +8,10 @@ type IEvent interface {
 	GetPayload() interface{}
 	SetPayload(payload interface{})
 }
+
+// ITenantEvent is implemented by events which carry the tenant they belong to
+type ITenantEvent interface {
+	IEvent
+	GetTenantId() string
+	SetTenantId(tenantId string)
+}
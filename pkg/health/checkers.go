@@ -0,0 +1,52 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+)
+
+type checkerFunc struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func (checker checkerFunc) Name() string {
+	return checker.name
+}
+
+func (checker checkerFunc) Check(ctx context.Context) error {
+	return checker.check(ctx)
+}
+
+// NewChecker creates a checker from a function
+func NewChecker(name string, check func(ctx context.Context) error) IChecker {
+	return checkerFunc{name: name, check: check}
+}
+
+// NewDatabaseChecker pings the database (e.g. the connection from database.NewConnection)
+func NewDatabaseChecker(name string, db *sql.DB) IChecker {
+	return NewChecker(name, db.PingContext)
+}
+
+// NewUpstreamChecker requests the URL (usually the health endpoint of the upstream) expecting a 2xx status
+func NewUpstreamChecker(name string, url string) IChecker {
+	return NewChecker(name, func(ctx context.Context) error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+			return fmt.Errorf("unexpected status code: %d", response.StatusCode)
+		}
+		return nil
+	})
+}
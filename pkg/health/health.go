@@ -0,0 +1,140 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/logger"
+)
+
+type Status string
+
+const (
+	Up   Status = "UP"
+	Down Status = "DOWN"
+)
+
+const (
+	DefaultTimeout  = 2 * time.Second
+	DefaultCacheTTL = 5 * time.Second
+)
+
+// IChecker checks a dependency of the service. Check must honour the context deadline
+type IChecker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// CheckResult is the outcome of a checker. The errors are logged, not reported: they may carry the DSNs, hosts or
+// broker addresses of the dependencies, and the readiness route is public
+type CheckResult struct {
+	Name       string    `json:"name"`
+	Status     Status    `json:"status"`
+	DurationMs int64     `json:"duration_ms"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+type Report struct {
+	Status Status        `json:"status"`
+	Checks []CheckResult `json:"checks,omitempty"`
+}
+
+// Health runs the checkers concurrently, each one limited by Timeout. The results are kept for CacheTTL, so frequent
+// probes from the orchestrator do not overload the dependencies
+type Health struct {
+	Timeout  time.Duration
+	CacheTTL time.Duration
+
+	checkers []IChecker
+	mutex    sync.Mutex
+	cache    map[string]CheckResult
+}
+
+func New(checkers ...IChecker) *Health {
+	return &Health{
+		Timeout:  DefaultTimeout,
+		CacheTTL: DefaultCacheTTL,
+		checkers: checkers,
+		cache:    make(map[string]CheckResult),
+	}
+}
+
+func (health *Health) Add(checker IChecker) *Health {
+	health.mutex.Lock()
+	defer health.mutex.Unlock()
+	health.checkers = append(health.checkers, checker)
+	return health
+}
+
+// Live reports the process is up. The dependencies are not checked: a dependency failure must not restart the service
+func (health *Health) Live() Report {
+	return Report{Status: Up}
+}
+
+// Ready reports whether the service can handle requests: it is UP only when all the checkers are UP
+func (health *Health) Ready(ctx context.Context) Report {
+	health.mutex.Lock()
+	checkers := append([]IChecker{}, health.checkers...)
+	health.mutex.Unlock()
+
+	report := Report{
+		Status: Up,
+		Checks: make([]CheckResult, len(checkers)),
+	}
+
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func(i int, checker IChecker) {
+			defer wg.Done()
+			report.Checks[i] = health.check(ctx, checker)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != Up {
+			report.Status = Down
+		}
+	}
+	return report
+}
+
+func (health *Health) check(ctx context.Context, checker IChecker) CheckResult {
+	if result, ok := health.cached(checker.Name()); ok {
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, health.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	result := CheckResult{
+		Name:       checker.Name(),
+		Status:     Up,
+		DurationMs: time.Since(start).Milliseconds(),
+		CheckedAt:  start,
+	}
+	if err != nil {
+		result.Status = Down
+		logger.GetLoggerWithContext(ctx).Errorf("Health check [%s] failed: %v", checker.Name(), err)
+	}
+
+	health.mutex.Lock()
+	health.cache[checker.Name()] = result
+	health.mutex.Unlock()
+	return result
+}
+
+func (health *Health) cached(name string) (CheckResult, bool) {
+	health.mutex.Lock()
+	defer health.mutex.Unlock()
+
+	result, ok := health.cache[name]
+	if !ok || time.Since(result.CheckedAt) > health.CacheTTL {
+		return CheckResult{}, false
+	}
+	return result, true
+}
@@ -0,0 +1,84 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/marcelofelixsalgado/financial-commons/settings"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReady(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	calls := 0
+	h := New(
+		NewUpstreamChecker("upstream", upstream.URL),
+		NewChecker("database", func(ctx context.Context) error {
+			calls++
+			return nil
+		}),
+	)
+
+	report := h.Ready(context.Background())
+	assert.Equal(t, Up, report.Status)
+	assert.Equal(t, "upstream", report.Checks[0].Name)
+	assert.Equal(t, Up, report.Checks[0].Status)
+	assert.Equal(t, "database", report.Checks[1].Name)
+
+	// Cached
+	h.Ready(context.Background())
+	assert.Equal(t, 1, calls)
+
+	h.CacheTTL = 0
+	h.Ready(context.Background())
+	assert.Equal(t, 2, calls)
+}
+
+func TestReadyDown(t *testing.T) {
+	settings.Config.LogLevel = "error"
+	settings.Config.LogAppFile = filepath.Join(t.TempDir(), "app.log")
+
+	h := New(
+		NewChecker("kafka", func(ctx context.Context) error {
+			return errors.New("brokers unavailable")
+		}),
+		NewChecker("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+	)
+	h.Timeout = 10 * time.Millisecond
+
+	report := h.Ready(context.Background())
+	assert.Equal(t, Down, report.Status)
+	assert.Equal(t, Down, report.Checks[0].Status)
+	assert.Equal(t, Down, report.Checks[1].Status)
+
+	// The errors are not reported
+	body, err := json.Marshal(report)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(body), "brokers unavailable")
+	assert.NotContains(t, string(body), context.DeadlineExceeded.Error())
+
+	assert.Equal(t, Up, h.Live().Status)
+}
+
+func TestUpstreamCheckerStatus(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+
+	err := NewUpstreamChecker("upstream", upstream.URL).Check(context.Background())
+	assert.EqualError(t, err, "unexpected status code: 503")
+}
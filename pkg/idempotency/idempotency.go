@@ -0,0 +1,42 @@
+package idempotency
+
+import (
+	"errors"
+	"time"
+)
+
+const Header = "Idempotency-Key"
+
+// ReplayedHeader is set on the responses replayed from the store
+const ReplayedHeader = "Idempotent-Replayed"
+
+type Status string
+
+const (
+	InProgress Status = "IN_PROGRESS"
+	Completed  Status = "COMPLETED"
+)
+
+var ErrRecordNotFound = errors.New("idempotency record not found")
+
+// Record is the state of an idempotency key: the fingerprint of the first request and, once completed, its response
+type Record struct {
+	Key            string
+	RequestHash    string
+	Status         Status
+	ResponseStatus int
+	ContentType    string
+	ResponseBody   []byte
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+}
+
+type IStore interface {
+	// Start creates an in progress record for the key. When the key is already taken (and not expired), the existing
+	// record is returned with started false
+	Start(key string, requestHash string, ttl time.Duration) (record Record, started bool, err error)
+	// Complete stores the response of the request which started the key
+	Complete(key string, responseStatus int, contentType string, responseBody []byte) error
+	// Release removes an in progress record, so the request can be retried (e.g. it failed with a server error)
+	Release(key string) error
+}
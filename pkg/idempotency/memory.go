@@ -0,0 +1,74 @@
+package idempotency
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps the records in memory. It is only suitable for a single instance of the service
+type MemoryStore struct {
+	mutex   sync.Mutex
+	records map[string]Record
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string]Record),
+	}
+}
+
+func (store *MemoryStore) Start(key string, requestHash string, ttl time.Duration) (Record, bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	now := time.Now()
+	store.removeExpired(now)
+
+	if record, ok := store.records[key]; ok {
+		return record, false, nil
+	}
+
+	record := Record{
+		Key:         key,
+		RequestHash: requestHash,
+		Status:      InProgress,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}
+	store.records[key] = record
+	return record, true, nil
+}
+
+func (store *MemoryStore) Complete(key string, responseStatus int, contentType string, responseBody []byte) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	record, ok := store.records[key]
+	if !ok {
+		return ErrRecordNotFound
+	}
+	record.Status = Completed
+	record.ResponseStatus = responseStatus
+	record.ContentType = contentType
+	record.ResponseBody = responseBody
+	store.records[key] = record
+	return nil
+}
+
+func (store *MemoryStore) Release(key string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if record, ok := store.records[key]; ok && record.Status == InProgress {
+		delete(store.records, key)
+	}
+	return nil
+}
+
+func (store *MemoryStore) removeExpired(now time.Time) {
+	for key, record := range store.records {
+		if now.After(record.ExpiresAt) {
+			delete(store.records, key)
+		}
+	}
+}
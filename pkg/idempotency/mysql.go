@@ -0,0 +1,119 @@
+package idempotency
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Duplicate entry error number (ER_DUP_ENTRY)
+const mysqlDuplicateEntry = 1062
+
+// MySQLStore keeps the records on a MySQL table, shared by all the instances of the service:
+//
+//	CREATE TABLE idempotency_keys (
+//		idempotency_key CHAR(64)     NOT NULL PRIMARY KEY,
+//		request_hash    CHAR(64)     NOT NULL,
+//		status          VARCHAR(16)  NOT NULL,
+//		response_status INT          NULL,
+//		content_type    VARCHAR(255) NULL,
+//		response_body   MEDIUMBLOB   NULL,
+//		created_at      DATETIME(3)  NOT NULL,
+//		expires_at      DATETIME(3)  NOT NULL,
+//		INDEX idx_idempotency_keys_expires_at (expires_at)
+//	)
+type MySQLStore struct {
+	DB    *sql.DB
+	Table string
+}
+
+func NewMySQLStore(db *sql.DB) *MySQLStore {
+	return &MySQLStore{
+		DB:    db,
+		Table: "idempotency_keys",
+	}
+}
+
+func (store *MySQLStore) Start(key string, requestHash string, ttl time.Duration) (Record, bool, error) {
+	now := time.Now().UTC()
+	record := Record{
+		Key:         key,
+		RequestHash: requestHash,
+		Status:      InProgress,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(ttl),
+	}
+
+	// Expired records are removed first, so the key can be taken again
+	deleteStatement := fmt.Sprintf("DELETE FROM %s WHERE idempotency_key = ? AND expires_at < ?", store.Table)
+	if _, err := store.DB.Exec(deleteStatement, key, now); err != nil {
+		return Record{}, false, err
+	}
+
+	insertStatement := fmt.Sprintf("INSERT INTO %s (idempotency_key, request_hash, status, created_at, expires_at) VALUES (?, ?, ?, ?, ?)", store.Table)
+	_, err := store.DB.Exec(insertStatement, record.Key, record.RequestHash, record.Status, record.CreatedAt, record.ExpiresAt)
+	if err == nil {
+		return record, true, nil
+	}
+
+	var mysqlError *mysql.MySQLError
+	if !errors.As(err, &mysqlError) || mysqlError.Number != mysqlDuplicateEntry {
+		return Record{}, false, err
+	}
+
+	existing, err := store.find(key)
+	if err != nil {
+		return Record{}, false, err
+	}
+	return existing, false, nil
+}
+
+func (store *MySQLStore) Complete(key string, responseStatus int, contentType string, responseBody []byte) error {
+	statement := fmt.Sprintf("UPDATE %s SET status = ?, response_status = ?, content_type = ?, response_body = ? WHERE idempotency_key = ?", store.Table)
+	result, err := store.DB.Exec(statement, Completed, responseStatus, contentType, responseBody, key)
+	if err != nil {
+		return err
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (store *MySQLStore) Release(key string) error {
+	statement := fmt.Sprintf("DELETE FROM %s WHERE idempotency_key = ? AND status = ?", store.Table)
+	_, err := store.DB.Exec(statement, key, InProgress)
+	return err
+}
+
+func (store *MySQLStore) find(key string) (Record, error) {
+	var (
+		record         Record
+		responseStatus sql.NullInt64
+		contentType    sql.NullString
+	)
+
+	statement := fmt.Sprintf("SELECT idempotency_key, request_hash, status, response_status, content_type, response_body, created_at, expires_at FROM %s WHERE idempotency_key = ?", store.Table)
+	err := store.DB.QueryRow(statement, key).Scan(
+		&record.Key,
+		&record.RequestHash,
+		&record.Status,
+		&responseStatus,
+		&contentType,
+		&record.ResponseBody,
+		&record.CreatedAt,
+		&record.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Record{}, ErrRecordNotFound
+	}
+	if err != nil {
+		return Record{}, err
+	}
+
+	record.ResponseStatus = int(responseStatus.Int64)
+	record.ContentType = contentType.String
+	return record, nil
+}
@@ -19,6 +19,10 @@ func NewConnection() *sql.DB {
 		settings.Config.DatabaseConnectionServerPort,
 		settings.Config.DatabaseName)
 
+	if timeout := settings.Config.DatabaseTimeout; timeout > 0 {
+		connectionString += fmt.Sprintf("&timeout=%ds&readTimeout=%ds&writeTimeout=%ds", timeout, timeout, timeout)
+	}
+
 	db, err := sql.Open("mysql", connectionString)
 	if err != nil {
 		logger.GetLogger().Fatalf("Error trying to connect to database: %v", err)
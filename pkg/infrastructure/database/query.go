@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tracing"
+)
+
+// QueryContext runs the query inside a client span. The query is not sent when the context deadline has already expired
+func QueryContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	span.RecordError(err)
+	return rows, err
+}
+
+// QueryRowContext runs the query inside a client span. Errors are deferred until the row is scanned, so they are not
+// recorded on the span
+func QueryRowContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) *sql.Row {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	return db.QueryRowContext(ctx, query, args...)
+}
+
+// ExecContext runs the statement inside a client span. The statement is not sent when the context deadline has already expired
+func ExecContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := startQuerySpan(ctx, query)
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	result, err := db.ExecContext(ctx, query, args...)
+	span.RecordError(err)
+	return result, err
+}
+
+func startQuerySpan(ctx context.Context, query string) (context.Context, *tracing.Span) {
+	operation := "query"
+	if fields := strings.Fields(query); len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+
+	ctx, span := tracing.StartSpan(ctx, "mysql "+operation, tracing.SpanKindClient)
+	span.SetAttribute("db.system", "mysql")
+	span.SetAttribute("db.operation", operation)
+	// Only the statement is recorded: the arguments may carry sensitive data
+	span.SetAttribute("db.statement", query)
+	if deadline, ok := ctx.Deadline(); ok {
+		span.SetAttribute("db.timeout_ms", time.Until(deadline).Milliseconds())
+	}
+	return ctx, span
+}
@@ -4,6 +4,8 @@ type FilterParameter struct {
 	Name     string
 	Value    string
 	Criteria Criteria
+	// Values of the List criteria (Value keeps them comma separated)
+	Values []string
 }
 
 type Criteria string
@@ -0,0 +1,34 @@
+package filter
+
+import (
+	"context"
+
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tenant"
+)
+
+// TenantFieldName is the filter name used to scope queries by tenant
+const TenantFieldName = "tenant_id"
+
+// ScopeByTenant appends the tenant filter taken from the context to the filter list.
+// Any tenant filter sent by the client is discarded, so a request can never reach
+// another tenant's data.
+func ScopeByTenant(ctx context.Context, filterParameters []FilterParameter) ([]FilterParameter, error) {
+	tenantId, err := tenant.Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scoped := make([]FilterParameter, 0, len(filterParameters)+1)
+	for _, filterParameter := range filterParameters {
+		if filterParameter.Name != TenantFieldName {
+			scoped = append(scoped, filterParameter)
+		}
+	}
+	scoped = append(scoped, FilterParameter{
+		Name:     TenantFieldName,
+		Value:    tenantId,
+		Criteria: Exact,
+	})
+
+	return scoped, nil
+}
@@ -0,0 +1,30 @@
+package filter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopeByTenant(t *testing.T) {
+	filterParameters := []FilterParameter{
+		{Name: "name", Value: "rent", Criteria: Exact},
+		{Name: TenantFieldName, Value: "another-tenant", Criteria: Exact},
+	}
+
+	scoped, err := ScopeByTenant(tenant.NewContext(context.Background(), "tenant-1"), filterParameters)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []FilterParameter{
+		{Name: "name", Value: "rent", Criteria: Exact},
+		{Name: TenantFieldName, Value: "tenant-1", Criteria: Exact},
+	}, scoped)
+}
+
+func TestScopeByTenantWithoutTenant(t *testing.T) {
+	_, err := ScopeByTenant(context.Background(), []FilterParameter{})
+
+	assert.Equal(t, tenant.ErrTenantNotFound, err)
+}
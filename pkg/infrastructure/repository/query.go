@@ -0,0 +1,349 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/marcelofelixsalgado/financial-commons/pkg/infrastructure/repository/filter"
+)
+
+var (
+	ErrInvalidIdentifier    = errors.New("invalid SQL identifier")
+	ErrColumnNotAllowed     = errors.New("column not allowed")
+	ErrUnsupportedCriteria  = errors.New("unsupported filter criteria")
+	ErrEmptyList            = errors.New("empty list filter")
+	ErrInvalidSortDirection = errors.New("invalid sort direction")
+	ErrInvalidKeyset        = errors.New("keyset does not match the sort")
+)
+
+// Escape character of the LIKE patterns. Not the backslash, which depends on the SQL mode (NO_BACKSLASH_ESCAPES)
+const likeEscape = "!"
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+var likeEscaper = strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+
+// Columns is the whitelist mapping the filter and sort field names to the table columns
+type Columns map[string]string
+
+// NewColumns creates a whitelist of fields named after the columns
+func NewColumns(names ...string) Columns {
+	columns := make(Columns, len(names))
+	for _, name := range names {
+		columns[name] = name
+	}
+	return columns
+}
+
+// MergeColumns returns the whitelist holding the fields of all the whitelists, e.g. the filter and the sort ones. The
+// later whitelists win for the fields in more than one
+func MergeColumns(whitelists ...Columns) Columns {
+	columns := make(Columns)
+	for _, whitelist := range whitelists {
+		for name, column := range whitelist {
+			columns[name] = column
+		}
+	}
+	return columns
+}
+
+type Direction string
+
+const (
+	Ascending  Direction = "ASC"
+	Descending Direction = "DESC"
+)
+
+type SortSpec struct {
+	Field     string
+	Direction Direction
+}
+
+// Keyset positions the page after the row holding the values of the sort fields (one value per Query.SortSpecs).
+// Backward pages are read before the row, in the reverse order
+type Keyset struct {
+	Values   []interface{}
+	Backward bool
+}
+
+// Query builds parameterized MySQL statements. Only the whitelisted columns reach the SQL text: the filter values are
+// always sent as arguments
+type Query struct {
+	Table   string
+	Columns Columns
+	// Fields selected. All the columns (*) when empty
+	Select  []string
+	Filters []filter.FilterParameter
+	Sort    []SortSpec
+	// Unique fields (e.g. the id) appended in ascending order to the sort when missing, so the rows with equal sort
+	// values keep a stable order across the pages
+	Tiebreakers []string
+	// No limit when zero
+	Limit  int
+	Offset int
+	// Keyset pagination (optional). Requires the sort or the tiebreakers
+	Keyset *Keyset
+}
+
+// ScopeByTenant returns a copy of the query restricted to the tenant of the context (see filter.ScopeByTenant)
+func (query Query) ScopeByTenant(ctx context.Context) (Query, error) {
+	filters, err := filter.ScopeByTenant(ctx, query.Filters)
+	if err != nil {
+		return Query{}, err
+	}
+	query.Filters = filters
+
+	if _, ok := query.Columns[filter.TenantFieldName]; !ok {
+		columns := make(Columns, len(query.Columns)+1)
+		for name, column := range query.Columns {
+			columns[name] = column
+		}
+		columns[filter.TenantFieldName] = filter.TenantFieldName
+		query.Columns = columns
+	}
+	return query, nil
+}
+
+// Build returns the SELECT statement and its arguments
+func (query Query) Build() (string, []interface{}, error) {
+	table, err := quoteIdentifier(query.Table)
+	if err != nil {
+		return "", nil, err
+	}
+
+	selected := "*"
+	if len(query.Select) > 0 {
+		columns := make([]string, 0, len(query.Select))
+		for _, field := range query.Select {
+			column, err := query.column(field)
+			if err != nil {
+				return "", nil, err
+			}
+			columns = append(columns, column)
+		}
+		selected = strings.Join(columns, ", ")
+	}
+
+	where, args, err := query.Where()
+	if err != nil {
+		return "", nil, err
+	}
+
+	keyset, keysetArgs, err := query.keysetCondition()
+	if err != nil {
+		return "", nil, err
+	}
+	if keyset != "" {
+		if where == "" {
+			where = " WHERE " + keyset
+		} else {
+			where += " AND " + keyset
+		}
+		args = append(args, keysetArgs...)
+	}
+
+	orderBy, err := query.OrderBy()
+	if err != nil {
+		return "", nil, err
+	}
+
+	statement := "SELECT " + selected + " FROM " + table + where + orderBy
+	if query.Limit > 0 {
+		statement += " LIMIT ?"
+		args = append(args, query.Limit)
+		if query.Offset > 0 {
+			statement += " OFFSET ?"
+			args = append(args, query.Offset)
+		}
+	}
+	return statement, args, nil
+}
+
+// BuildCount returns the statement counting the rows matching the filters (sorting and paging, keyset included, are
+// ignored)
+func (query Query) BuildCount() (string, []interface{}, error) {
+	table, err := quoteIdentifier(query.Table)
+	if err != nil {
+		return "", nil, err
+	}
+
+	where, args, err := query.Where()
+	if err != nil {
+		return "", nil, err
+	}
+	return "SELECT COUNT(*) FROM " + table + where, args, nil
+}
+
+// Where returns the WHERE clause (with a leading space, empty when there are no filters) and its arguments.
+// The filters are combined with AND
+func (query Query) Where() (string, []interface{}, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	for _, filterParameter := range query.Filters {
+		column, err := query.column(filterParameter.Name)
+		if err != nil {
+			return "", nil, err
+		}
+
+		switch filterParameter.Criteria {
+		case filter.Exact, "":
+			conditions = append(conditions, column+" = ?")
+			args = append(args, filterParameter.Value)
+		case filter.Like:
+			conditions = append(conditions, column+" LIKE ? ESCAPE '"+likeEscape+"'")
+			args = append(args, "%"+likeEscaper.Replace(filterParameter.Value)+"%")
+		case filter.List:
+			values := filterParameter.Values
+			if values == nil && filterParameter.Value != "" {
+				values = strings.Split(filterParameter.Value, ",")
+			}
+			if len(values) == 0 {
+				return "", nil, fmt.Errorf("%w: %s", ErrEmptyList, filterParameter.Name)
+			}
+			conditions = append(conditions, column+" IN ("+strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")+")")
+			for _, value := range values {
+				args = append(args, value)
+			}
+		case filter.Greater:
+			conditions = append(conditions, column+" > ?")
+			args = append(args, filterParameter.Value)
+		case filter.GreaterOrEqual:
+			conditions = append(conditions, column+" >= ?")
+			args = append(args, filterParameter.Value)
+		case filter.Lesser:
+			conditions = append(conditions, column+" < ?")
+			args = append(args, filterParameter.Value)
+		case filter.LesserOrEqual:
+			conditions = append(conditions, column+" <= ?")
+			args = append(args, filterParameter.Value)
+		default:
+			return "", nil, fmt.Errorf("%w: %s", ErrUnsupportedCriteria, filterParameter.Criteria)
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// OrderBy returns the ORDER BY clause (with a leading space, empty when there is no sorting). The directions are
+// reversed on backward keyset pages
+func (query Query) OrderBy() (string, error) {
+	sortSpecs := query.SortSpecs()
+	if len(sortSpecs) == 0 {
+		return "", nil
+	}
+
+	terms := make([]string, 0, len(sortSpecs))
+	for _, sortSpec := range sortSpecs {
+		column, direction, err := query.sortTerm(sortSpec)
+		if err != nil {
+			return "", err
+		}
+		terms = append(terms, column+" "+string(direction))
+	}
+	return " ORDER BY " + strings.Join(terms, ", "), nil
+}
+
+// SortSpecs returns the sort followed by the missing tiebreakers. The keyset holds one value per spec
+func (query Query) SortSpecs() []SortSpec {
+	sortSpecs := query.Sort
+	for _, tiebreaker := range query.Tiebreakers {
+		sorted := false
+		for _, sortSpec := range query.Sort {
+			if sortSpec.Field == tiebreaker {
+				sorted = true
+				break
+			}
+		}
+		if !sorted {
+			sortSpecs = append(sortSpecs[:len(sortSpecs):len(sortSpecs)], SortSpec{Field: tiebreaker, Direction: Ascending})
+		}
+	}
+	return sortSpecs
+}
+
+// keysetCondition returns the condition selecting the rows after the keyset, e.g. for date DESC, id ASC:
+// (date < ? OR (date = ? AND id > ?))
+func (query Query) keysetCondition() (string, []interface{}, error) {
+	if query.Keyset == nil {
+		return "", nil, nil
+	}
+	sortSpecs := query.SortSpecs()
+	if len(sortSpecs) == 0 || len(query.Keyset.Values) != len(sortSpecs) {
+		return "", nil, ErrInvalidKeyset
+	}
+
+	var (
+		alternatives []string
+		args         []interface{}
+		equalities   []string
+	)
+	for i, sortSpec := range sortSpecs {
+		column, direction, err := query.sortTerm(sortSpec)
+		if err != nil {
+			return "", nil, err
+		}
+		operator := " > ?"
+		if direction == Descending {
+			operator = " < ?"
+		}
+
+		alternative := append(equalities[:len(equalities):len(equalities)], column+operator)
+		if len(alternative) == 1 {
+			alternatives = append(alternatives, alternative[0])
+		} else {
+			alternatives = append(alternatives, "("+strings.Join(alternative, " AND ")+")")
+		}
+		args = append(args, query.Keyset.Values[:i+1]...)
+		equalities = append(equalities, column+" = ?")
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", args, nil
+}
+
+// sortTerm returns the quoted column and the direction of the sort, reversed on backward keyset pages
+func (query Query) sortTerm(sortSpec SortSpec) (string, Direction, error) {
+	column, err := query.column(sortSpec.Field)
+	if err != nil {
+		return "", "", err
+	}
+	direction := sortSpec.Direction
+	if direction == "" {
+		direction = Ascending
+	}
+	if direction != Ascending && direction != Descending {
+		return "", "", fmt.Errorf("%w: %s", ErrInvalidSortDirection, direction)
+	}
+	if query.Keyset != nil && query.Keyset.Backward {
+		if direction == Ascending {
+			direction = Descending
+		} else {
+			direction = Ascending
+		}
+	}
+	return column, direction, nil
+}
+
+// column returns the quoted column of the whitelisted field
+func (query Query) column(field string) (string, error) {
+	column, ok := query.Columns[field]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrColumnNotAllowed, field)
+	}
+	return quoteIdentifier(column)
+}
+
+// quoteIdentifier quotes the identifier (optionally qualified by the table) with backticks
+func quoteIdentifier(identifier string) (string, error) {
+	if !identifierRegexp.MatchString(identifier) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidIdentifier, identifier)
+	}
+	return "`" + strings.ReplaceAll(identifier, ".", "`.`") + "`", nil
+}
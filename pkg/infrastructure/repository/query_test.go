@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/marcelofelixsalgado/financial-commons/pkg/infrastructure/repository/filter"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tenant"
+	"github.com/stretchr/testify/assert"
+)
+
+var transactionColumns = Columns{
+	"id":          "id",
+	"description": "description",
+	"amount":      "amount",
+	"date":        "transaction_date",
+	"status":      "status",
+}
+
+func TestQueryBuild(t *testing.T) {
+	query := Query{
+		Table:   "transactions",
+		Columns: transactionColumns,
+		Select:  []string{"id", "amount"},
+		Filters: []filter.FilterParameter{
+			{Name: "description", Value: "50%_off!", Criteria: filter.Like},
+			{Name: "status", Value: "open,paid", Criteria: filter.List, Values: []string{"open", "paid"}},
+			{Name: "amount", Value: "10", Criteria: filter.GreaterOrEqual},
+			{Name: "amount", Value: "100", Criteria: filter.Lesser},
+			{Name: "date", Value: "2023-01-01", Criteria: filter.Greater},
+			{Name: "date", Value: "2023-12-31", Criteria: filter.LesserOrEqual},
+			{Name: "id", Value: "1"},
+		},
+		Sort:   []SortSpec{{Field: "date", Direction: Descending}, {Field: "id"}},
+		Limit:  20,
+		Offset: 40,
+	}
+
+	statement, args, err := query.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT `id`, `amount` FROM `transactions`"+
+		" WHERE `description` LIKE ? ESCAPE '!' AND `status` IN (?, ?) AND `amount` >= ? AND `amount` < ?"+
+		" AND `transaction_date` > ? AND `transaction_date` <= ? AND `id` = ?"+
+		" ORDER BY `transaction_date` DESC, `id` ASC LIMIT ? OFFSET ?", statement)
+	assert.Equal(t, []interface{}{"%50!%!_off!!%", "open", "paid", "10", "100", "2023-01-01", "2023-12-31", "1", 20, 40}, args)
+
+	statement, args, err = query.BuildCount()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM `transactions`"+
+		" WHERE `description` LIKE ? ESCAPE '!' AND `status` IN (?, ?) AND `amount` >= ? AND `amount` < ?"+
+		" AND `transaction_date` > ? AND `transaction_date` <= ? AND `id` = ?", statement)
+	assert.Equal(t, 8, len(args))
+}
+
+func TestQueryScopeByTenant(t *testing.T) {
+	query := Query{
+		Table:   "transactions",
+		Columns: transactionColumns,
+		Filters: []filter.FilterParameter{{Name: filter.TenantFieldName, Value: "another-tenant"}},
+	}
+
+	scoped, err := query.ScopeByTenant(tenant.NewContext(context.Background(), "tenant-1"))
+	assert.Nil(t, err)
+
+	statement, args, err := scoped.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM `transactions` WHERE `tenant_id` = ?", statement)
+	assert.Equal(t, []interface{}{"tenant-1"}, args)
+
+	// The whitelist of the original query is not changed
+	_, ok := transactionColumns[filter.TenantFieldName]
+	assert.False(t, ok)
+
+	_, err = query.ScopeByTenant(context.Background())
+	assert.Equal(t, tenant.ErrTenantNotFound, err)
+}
+
+func TestQueryBuildErrors(t *testing.T) {
+	tests := []struct {
+		query Query
+		err   error
+	}{
+		{Query{Table: "transactions; DROP TABLE users", Columns: transactionColumns}, ErrInvalidIdentifier},
+		{Query{Table: "transactions", Columns: transactionColumns, Filters: []filter.FilterParameter{{Name: "password", Value: "1"}}}, ErrColumnNotAllowed},
+		{Query{Table: "transactions", Columns: Columns{"id": "id` OR 1=1"}, Filters: []filter.FilterParameter{{Name: "id", Value: "1"}}}, ErrInvalidIdentifier},
+		{Query{Table: "transactions", Columns: transactionColumns, Filters: []filter.FilterParameter{{Name: "id", Criteria: filter.List}}}, ErrEmptyList},
+		{Query{Table: "transactions", Columns: transactionColumns, Filters: []filter.FilterParameter{{Name: "id", Criteria: "regexp"}}}, ErrUnsupportedCriteria},
+		{Query{Table: "transactions", Columns: transactionColumns, Sort: []SortSpec{{Field: "id", Direction: "RANDOM"}}}, ErrInvalidSortDirection},
+	}
+
+	for _, test := range tests {
+		_, _, err := test.query.Build()
+		assert.True(t, errors.Is(err, test.err), err)
+	}
+}
+
+func TestQueryBuildKeyset(t *testing.T) {
+	query := Query{
+		Table:   "transactions",
+		Columns: transactionColumns,
+		Filters: []filter.FilterParameter{{Name: "status", Value: "open"}},
+		Sort:    []SortSpec{{Field: "date", Direction: Descending}, {Field: "amount"}, {Field: "id"}},
+		Limit:   21,
+		Keyset:  &Keyset{Values: []interface{}{"2023-01-08", "10", "8"}},
+	}
+
+	statement, args, err := query.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM `transactions` WHERE `status` = ?"+
+		" AND (`transaction_date` < ? OR (`transaction_date` = ? AND `amount` > ?) OR (`transaction_date` = ? AND `amount` = ? AND `id` > ?))"+
+		" ORDER BY `transaction_date` DESC, `amount` ASC, `id` ASC LIMIT ?", statement)
+	assert.Equal(t, []interface{}{"open", "2023-01-08", "2023-01-08", "10", "2023-01-08", "10", "8", 21}, args)
+
+	// The previous page is read backwards
+	query.Filters = nil
+	query.Keyset.Backward = true
+	statement, _, err = query.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM `transactions`"+
+		" WHERE (`transaction_date` > ? OR (`transaction_date` = ? AND `amount` < ?) OR (`transaction_date` = ? AND `amount` = ? AND `id` < ?))"+
+		" ORDER BY `transaction_date` ASC, `amount` DESC, `id` DESC LIMIT ?", statement)
+
+	// The count ignores the keyset
+	statement, _, err = query.BuildCount()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT COUNT(*) FROM `transactions`", statement)
+
+	query.Keyset.Values = query.Keyset.Values[:1]
+	_, _, err = query.Build()
+	assert.Equal(t, ErrInvalidKeyset, err)
+}
+
+func TestQueryTiebreakers(t *testing.T) {
+	query := Query{
+		Table:       "transactions",
+		Columns:     transactionColumns,
+		Sort:        []SortSpec{{Field: "date", Direction: Descending}},
+		Tiebreakers: []string{"id"},
+		Keyset:      &Keyset{Values: []interface{}{"2023-01-08", "8"}},
+	}
+
+	assert.Equal(t, []SortSpec{{Field: "date", Direction: Descending}, {Field: "id", Direction: Ascending}}, query.SortSpecs())
+
+	statement, args, err := query.Build()
+	assert.Nil(t, err)
+	assert.Equal(t, "SELECT * FROM `transactions`"+
+		" WHERE (`transaction_date` < ? OR (`transaction_date` = ? AND `id` > ?))"+
+		" ORDER BY `transaction_date` DESC, `id` ASC", statement)
+	assert.Equal(t, []interface{}{"2023-01-08", "2023-01-08", "8"}, args)
+
+	// The tiebreakers already sorted keep the direction of the client
+	query.Sort = []SortSpec{{Field: "id", Direction: Descending}}
+	query.Keyset = nil
+	assert.Equal(t, []SortSpec{{Field: "id", Direction: Descending}}, query.SortSpecs())
+
+	// Sorted by the tiebreakers when there is no sort
+	query.Sort = nil
+	orderBy, err := query.OrderBy()
+	assert.Nil(t, err)
+	assert.Equal(t, " ORDER BY `id` ASC", orderBy)
+}
@@ -2,31 +2,82 @@ package requests
 
 import (
 	"bytes"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/auth"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/correlation"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/deadline"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tracing"
 )
 
-// Make a request to another backend (upstream)
+// Make a request to another backend (upstream). The request is cancelled when the deadline of the incoming
+// request expires, and the remaining time is sent to the upstream on the X-Request-Timeout header
 func MakeUpstreamRequest(ctx echo.Context, method, url string, data []byte, authenticated bool) (*http.Response, error) {
 
-	request, err := http.NewRequest(method, url, bytes.NewBuffer(data))
+	spanContext, span := tracing.StartSpan(ctx.Request().Context(), fmt.Sprintf("HTTP %s", method), tracing.SpanKindClient)
+	defer span.End()
+	span.SetAttribute("http.method", method)
+	span.SetAttribute("http.url", spanURL(url))
+
+	request, err := http.NewRequestWithContext(spanContext, method, url, bytes.NewBuffer(data))
 	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
 
+	correlation.SetHeaders(spanContext, request.Header)
+	tracing.Inject(spanContext, request.Header)
+	deadline.SetHeader(spanContext, request.Header)
+
 	if authenticated {
-		// Get the access token from the cookie and set the upstream request header
-		// customContext := ctx.(*context.CustomContext)
-		// accessToken := fmt.Sprintf("Bearer %s", customContext.Get("client"))
-		request.Header.Add("Authorization", ctx.Request().Header.Get("Authorization"))
+		// Forward the access token the request was authenticated with (header, cookie or query)
+		accessToken, err := forwardedToken(ctx.Request())
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", accessToken))
 	}
 
 	client := &http.Client{}
 	response, err := client.Do(request)
 	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
 
+	span.SetAttribute("http.status_code", response.StatusCode)
+	if response.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(tracing.StatusError, http.StatusText(response.StatusCode))
+	}
+
 	return response, nil
 }
+
+// forwardedToken returns the bearer token of the authenticated identity, or the one sent on the Authorization header
+func forwardedToken(r *http.Request) (string, error) {
+	if identity, ok := auth.IdentityFromContext(r.Context()); ok && identity.AccessToken() != "" {
+		return identity.AccessToken(), nil
+	}
+	return auth.ExtractToken(r)
+}
+
+// spanURL returns the URL without the query string, the fragment and the user info, which may carry credentials (e.g.
+// tokens and API keys sent as query parameters)
+func spanURL(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		withoutQuery, _, _ := strings.Cut(rawURL, "?")
+		return withoutQuery
+	}
+	parsedURL.User = nil
+	parsedURL.RawQuery = ""
+	parsedURL.ForceQuery = false
+	parsedURL.Fragment = ""
+	parsedURL.RawFragment = ""
+	return parsedURL.String()
+}
@@ -1,12 +1,25 @@
 package kafka
 
-import ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+import (
+	"context"
+	"errors"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/logger"
+	"github.com/sirupsen/logrus"
+)
 
 type Consumer struct {
 	ConfigMap *ckafka.ConfigMap
 	Topics    []string
+	// Logs the consumed payloads (masked) at debug level
+	LogPayloads bool
 }
 
+// MessageHandler processes a message. The context carries the tenant, correlation ids and the consumer span
+type MessageHandler func(ctx context.Context, msg *ckafka.Message) error
+
 func NewConsumer(configMap *ckafka.ConfigMap, topics []string) *Consumer {
 	return &Consumer{
 		ConfigMap: configMap,
@@ -30,3 +43,46 @@ func (c *Consumer) Consume(msgChan chan *ckafka.Message) error {
 		}
 	}
 }
+
+// ConsumeWithContext reads the messages until the context is cancelled, handling each one inside a consumer span
+// which continues the trace of the producer. The read errors are logged, except the fatal ones which stop the consumer
+// and are returned
+func (c *Consumer) ConsumeWithContext(ctx context.Context, handler MessageHandler) error {
+	consumer, err := ckafka.NewConsumer(c.ConfigMap)
+	if err != nil {
+		return err
+	}
+	defer consumer.Close()
+
+	if err := consumer.SubscribeTopics(c.Topics, nil); err != nil {
+		return err
+	}
+
+	for ctx.Err() == nil {
+		msg, err := consumer.ReadMessage(time.Second)
+		if err != nil {
+			// Timeouts are expected while there are no messages
+			var kafkaError ckafka.Error
+			if errors.As(err, &kafkaError) && kafkaError.Code() == ckafka.ErrTimedOut {
+				continue
+			}
+			if errors.As(err, &kafkaError) && !kafkaError.IsFatal() {
+				logger.GetLoggerWithContext(ctx).Errorf("Error reading the message: %v", err)
+				continue
+			}
+			return err
+		}
+
+		msgCtx, span := StartConsumerSpan(ctx, msg)
+		if c.LogPayloads {
+			logger.GetLoggerWithContext(msgCtx).WithFields(logrus.Fields{
+				"topic":   messageTopic(msg),
+				"offset":  int64(msg.TopicPartition.Offset),
+				"payload": MaskPayload(msg.Value),
+			}).Debug("Message received")
+		}
+		span.RecordError(handler(msgCtx, msg))
+		span.End()
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/correlation"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tenant"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tracing"
+)
+
+// HeaderValue returns the value of the first message header with the given key
+func HeaderValue(msg *ckafka.Message, key string) string {
+	for _, header := range msg.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+// ContextFromMessage returns a copy of the parent context carrying the values stamped on the message headers (tenant,
+// request and correlation ids and trace context)
+func ContextFromMessage(parent context.Context, msg *ckafka.Message) context.Context {
+	ctx := parent
+	if tenantId := HeaderValue(msg, tenant.HeaderName); tenantId != "" {
+		ctx = tenant.NewContext(ctx, tenantId)
+	}
+	if correlationId := HeaderValue(msg, correlation.CorrelationIDHeader); correlationId != "" {
+		ctx = correlation.NewContext(ctx, correlation.IDs{
+			RequestID:     HeaderValue(msg, correlation.RequestIDHeader),
+			CorrelationID: correlationId,
+		})
+	}
+	if spanContext, err := tracing.ParseTraceparent(HeaderValue(msg, tracing.TraceparentHeader)); err == nil {
+		ctx = tracing.ContextWithRemoteSpanContext(ctx, spanContext)
+	}
+	return ctx
+}
+
+// StartConsumerSpan starts a consumer span for the message, continuing the trace of the producer. The span must be
+// ended by the caller once the message is processed
+func StartConsumerSpan(parent context.Context, msg *ckafka.Message) (context.Context, *tracing.Span) {
+	topic := messageTopic(msg)
+
+	ctx, span := tracing.StartSpan(ContextFromMessage(parent, msg), fmt.Sprintf("%s process", topic), tracing.SpanKindConsumer)
+	span.SetAttribute("messaging.system", "kafka")
+	span.SetAttribute("messaging.destination", topic)
+	span.SetAttribute("messaging.kafka.partition", msg.TopicPartition.Partition)
+	span.SetAttribute("messaging.kafka.offset", int64(msg.TopicPartition.Offset))
+	return ctx, span
+}
+
+// messageTopic returns the topic of the message, empty when it is not set
+func messageTopic(msg *ckafka.Message) string {
+	if msg.TopicPartition.Topic == nil {
+		return ""
+	}
+	return *msg.TopicPartition.Topic
+}
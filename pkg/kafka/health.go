@@ -0,0 +1,27 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/health"
+)
+
+// NewHealthChecker checks the connectivity with the brokers by requesting the cluster metadata
+func NewHealthChecker(name string, configMap *ckafka.ConfigMap) health.IChecker {
+	return health.NewChecker(name, func(ctx context.Context) error {
+		client, err := ckafka.NewAdminClient(configMap)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+
+		timeout := health.DefaultTimeout
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = time.Until(deadline)
+		}
+		_, err = client.GetMetadata(nil, false, int(timeout.Milliseconds()))
+		return err
+	})
+}
@@ -0,0 +1,15 @@
+package kafka
+
+import "github.com/marcelofelixsalgado/financial-commons/pkg/commons/masking"
+
+var payloadMasker = masking.Default()
+
+// SetPayloadMasker replaces the masker applied to the logged payloads (masking.Default() by default)
+func SetPayloadMasker(masker *masking.Masker) {
+	payloadMasker = masker
+}
+
+// MaskPayload masks the sensitive data of a message payload, so it can be logged
+func MaskPayload(payload []byte) string {
+	return payloadMasker.MaskJSON(string(payload))
+}
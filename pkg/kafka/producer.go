@@ -1,14 +1,23 @@
 package kafka
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	ckafka "github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/commons/logger"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/correlation"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tenant"
+	"github.com/marcelofelixsalgado/financial-commons/pkg/tracing"
+	"github.com/sirupsen/logrus"
 )
 
 type Producer struct {
 	ConfigMap *ckafka.ConfigMap
+	// Logs the published payloads (masked) at debug level
+	LogPayloads bool
 }
 
 func NewKafkaProducer(configMap *ckafka.ConfigMap) *Producer {
@@ -16,20 +25,41 @@ func NewKafkaProducer(configMap *ckafka.ConfigMap) *Producer {
 }
 
 func (p *Producer) Publish(msg interface{}, key []byte, topic string, deliveryChan chan kafka.Event) error {
+	return p.PublishWithContext(context.Background(), msg, key, topic, deliveryChan)
+}
+
+// PublishWithContext publishes the message stamping the headers with the values carried by the context (tenant,
+// request and correlation ids and trace context)
+func (p *Producer) PublishWithContext(ctx context.Context, msg interface{}, key []byte, topic string, deliveryChan chan kafka.Event) error {
+	ctx, span := tracing.StartSpan(ctx, fmt.Sprintf("%s publish", topic), tracing.SpanKindProducer)
+	defer span.End()
+	span.SetAttribute("messaging.system", "kafka")
+	span.SetAttribute("messaging.destination", topic)
+
 	producer, err := ckafka.NewProducer(p.ConfigMap)
 	if err != nil {
+		span.RecordError(err)
 		return err
 	}
 
 	msgJson, err := json.Marshal(msg)
 	if err != nil {
+		span.RecordError(err)
 		return err
 	}
 
+	if p.LogPayloads {
+		logger.GetLoggerWithContext(ctx).WithFields(logrus.Fields{
+			"topic":   topic,
+			"payload": MaskPayload(msgJson),
+		}).Debug("Publishing message")
+	}
+
 	message := &ckafka.Message{
 		TopicPartition: ckafka.TopicPartition{Topic: &topic, Partition: ckafka.PartitionAny},
 		Value:          msgJson,
 		Key:            key,
+		Headers:        headersFromContext(ctx),
 	}
 	err = producer.Produce(message, deliveryChan)
 	if err != nil {
@@ -39,3 +69,19 @@ func (p *Producer) Publish(msg interface{}, key []byte, topic string, deliveryCh
 	producer.Flush(1000)
 	return nil
 }
+
+func headersFromContext(ctx context.Context) []ckafka.Header {
+	var headers []ckafka.Header
+	if tenantId, ok := tenant.FromContext(ctx); ok {
+		headers = append(headers, ckafka.Header{Key: tenant.HeaderName, Value: []byte(tenantId)})
+	}
+	if ids, ok := correlation.FromContext(ctx); ok {
+		headers = append(headers,
+			ckafka.Header{Key: correlation.RequestIDHeader, Value: []byte(ids.RequestID)},
+			ckafka.Header{Key: correlation.CorrelationIDHeader, Value: []byte(ids.CorrelationID)})
+	}
+	if spanContext := tracing.SpanContextFromContext(ctx); spanContext.IsValid() {
+		headers = append(headers, ckafka.Header{Key: tracing.TraceparentHeader, Value: []byte(spanContext.Traceparent())})
+	}
+	return headers
+}
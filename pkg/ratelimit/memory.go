@@ -0,0 +1,167 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type tokenBucket struct {
+	tokens     float64
+	lastRefill time.Time
+	expiresAt  time.Time
+}
+
+type slidingWindow struct {
+	windowStart   time.Time
+	currentCount  int
+	previousCount int
+	expiresAt     time.Time
+}
+
+// MemoryStore keeps the state in memory. It is only suitable for a single instance of the service
+type MemoryStore struct {
+	mutex        sync.Mutex
+	buckets      map[string]*tokenBucket
+	windows      map[string]*slidingWindow
+	lastCleanup  time.Time
+	cleanupEvery time.Duration
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:      make(map[string]*tokenBucket),
+		windows:      make(map[string]*slidingWindow),
+		cleanupEvery: time.Minute,
+	}
+}
+
+func (store *MemoryStore) Take(key string, policy Policy, now time.Time) (Result, error) {
+	if err := policy.Validate(); err != nil {
+		return Result{}, err
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.cleanup(now)
+
+	if policy.Algorithm == TokenBucket {
+		return store.takeToken(key, policy, now), nil
+	}
+	return store.takeWindow(key, policy, now), nil
+}
+
+func (store *MemoryStore) takeToken(key string, policy Policy, now time.Time) Result {
+	capacity := float64(policy.capacity())
+	refillRate := float64(policy.Limit) / float64(policy.Period) // tokens per nanosecond
+
+	bucket, ok := store.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, lastRefill: now}
+		store.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.lastRefill)
+	if elapsed > 0 {
+		bucket.tokens = math.Min(capacity, bucket.tokens+float64(elapsed)*refillRate)
+		bucket.lastRefill = now
+	}
+
+	result := Result{Limit: policy.capacity()}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1 - bucket.tokens) / refillRate))
+	}
+
+	result.Remaining = int(bucket.tokens)
+	result.ResetAfter = time.Duration(math.Ceil((capacity - bucket.tokens) / refillRate))
+	bucket.expiresAt = now.Add(result.ResetAfter)
+	return result
+}
+
+func (store *MemoryStore) takeWindow(key string, policy Policy, now time.Time) Result {
+	currentStart := now.Truncate(policy.Period)
+
+	window, ok := store.windows[key]
+	if !ok {
+		window = &slidingWindow{windowStart: currentStart}
+		store.windows[key] = window
+	}
+
+	switch {
+	case window.windowStart.Equal(currentStart):
+	case window.windowStart.Add(policy.Period).Equal(currentStart):
+		window.previousCount = window.currentCount
+		window.currentCount = 0
+		window.windowStart = currentStart
+	default:
+		window.previousCount = 0
+		window.currentCount = 0
+		window.windowStart = currentStart
+	}
+
+	// Share of the previous window which still overlaps the sliding window
+	previousWeight := 1 - float64(now.Sub(currentStart))/float64(policy.Period)
+	estimated := float64(window.previousCount)*previousWeight + float64(window.currentCount)
+
+	result := Result{Limit: policy.Limit}
+	if estimated+1 <= float64(policy.Limit) {
+		window.currentCount++
+		estimated++
+		result.Allowed = true
+	} else {
+		result.RetryAfter = window.retryAfter(policy, now, previousWeight)
+	}
+
+	result.Remaining = int(math.Max(0, math.Floor(float64(policy.Limit)-estimated)))
+	result.ResetAfter = currentStart.Add(policy.Period).Sub(now)
+	if window.currentCount > 0 {
+		// Requests of the current window still weigh on the next one
+		result.ResetAfter += policy.Period
+	}
+	window.expiresAt = currentStart.Add(2 * policy.Period)
+	return result
+}
+
+// retryAfter estimates when the weighted count drops enough to accept one more request
+func (window *slidingWindow) retryAfter(policy Policy, now time.Time, previousWeight float64) time.Duration {
+	untilNextWindow := window.windowStart.Add(policy.Period).Sub(now)
+
+	if window.previousCount > 0 && window.currentCount < policy.Limit {
+		// The previous window weight decreases linearly until the end of the current window
+		excess := float64(window.previousCount)*previousWeight + float64(window.currentCount) + 1 - float64(policy.Limit)
+		wait := time.Duration(math.Ceil(excess / float64(window.previousCount) * float64(policy.Period)))
+		if wait < untilNextWindow {
+			return wait
+		}
+	}
+
+	if window.currentCount < policy.Limit {
+		return untilNextWindow
+	}
+
+	// On the next window the current count becomes the previous one and must lose weight too
+	nextWeight := float64(policy.Limit-1) / float64(window.currentCount)
+	return untilNextWindow + time.Duration(math.Ceil((1-nextWeight)*float64(policy.Period)))
+}
+
+func (store *MemoryStore) cleanup(now time.Time) {
+	if now.Sub(store.lastCleanup) < store.cleanupEvery {
+		return
+	}
+	store.lastCleanup = now
+
+	for key, bucket := range store.buckets {
+		if now.After(bucket.expiresAt) {
+			delete(store.buckets, key)
+		}
+	}
+	for key, window := range store.windows {
+		if now.After(window.expiresAt) {
+			delete(store.windows, key)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	store := NewMemoryStore()
+	policy := Policy{Name: "exports", Algorithm: TokenBucket, Key: ByUser, Limit: 1, Period: time.Second, Burst: 3}
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 3; i++ {
+		result, err := store.Take("user-1", policy, now)
+		assert.Nil(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2-i, result.Remaining)
+	}
+
+	result, _ := store.Take("user-1", policy, now)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.ResetAfter)
+
+	// Other keys have their own bucket
+	result, _ = store.Take("user-2", policy, now)
+	assert.True(t, result.Allowed)
+
+	result, _ = store.Take("user-1", policy, now.Add(time.Second))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+}
+
+func TestSlidingWindow(t *testing.T) {
+	store := NewMemoryStore()
+	policy := Policy{Name: "login", Algorithm: SlidingWindow, Key: ByIP, Limit: 4, Period: time.Minute}
+	start := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 4; i++ {
+		result, err := store.Take("ip-1", policy, start.Add(30*time.Second))
+		assert.Nil(t, err)
+		assert.True(t, result.Allowed)
+	}
+
+	result, _ := store.Take("ip-1", policy, start.Add(59*time.Second))
+	assert.False(t, result.Allowed)
+	// 1s until the next window plus 15s until the previous window weighs 3/4
+	assert.Equal(t, 16*time.Second, result.RetryAfter)
+
+	// 15s into the next window the previous one still weighs 3 requests
+	result, _ = store.Take("ip-1", policy, start.Add(75*time.Second))
+	assert.True(t, result.Allowed)
+	result, _ = store.Take("ip-1", policy, start.Add(75*time.Second))
+	assert.False(t, result.Allowed)
+
+	// Windows without requests reset the count
+	result, _ = store.Take("ip-1", policy, start.Add(5*time.Minute))
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 3, result.Remaining)
+}
+
+func TestInvalidPolicy(t *testing.T) {
+	store := NewMemoryStore()
+
+	_, err := store.Take("key", Policy{Algorithm: "leaky_bucket", Key: ByIP, Limit: 1, Period: time.Second}, time.Now())
+	assert.Equal(t, ErrInvalidPolicy, err)
+
+	_, err = store.Take("key", Policy{Algorithm: TokenBucket, Key: ByIP, Limit: 0, Period: time.Second}, time.Now())
+	assert.Equal(t, ErrInvalidPolicy, err)
+}
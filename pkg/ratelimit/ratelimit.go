@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"errors"
+	"time"
+)
+
+type Algorithm string
+
+const (
+	// TokenBucket allows bursts up to Burst requests, refilling Limit tokens per Period
+	TokenBucket Algorithm = "token_bucket"
+	// SlidingWindow allows Limit requests in any window of Period, weighting the previous fixed window
+	SlidingWindow Algorithm = "sliding_window"
+)
+
+type KeyType string
+
+const (
+	ByIP     KeyType = "ip"
+	ByUser   KeyType = "user"
+	ByTenant KeyType = "tenant"
+)
+
+var ErrInvalidPolicy = errors.New("invalid rate limit policy")
+
+type Policy struct {
+	Name      string
+	Algorithm Algorithm
+	Key       KeyType
+	Limit     int
+	Period    time.Duration
+	Burst     int // token bucket capacity. Defaults to Limit
+}
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // time until the next request is allowed, when not allowed
+	ResetAfter time.Duration // time until the limit is fully restored
+}
+
+// IStore keeps the rate limit state. Implementations must apply the policy atomically, since the same key is
+// shared by concurrent requests (and by all the instances of the service for shared stores)
+type IStore interface {
+	Take(key string, policy Policy, now time.Time) (Result, error)
+}
+
+func (policy Policy) Validate() error {
+	if policy.Limit <= 0 || policy.Period <= 0 || policy.Burst < 0 {
+		return ErrInvalidPolicy
+	}
+	switch policy.Algorithm {
+	case TokenBucket, SlidingWindow:
+	default:
+		return ErrInvalidPolicy
+	}
+	switch policy.Key {
+	case ByIP, ByUser, ByTenant:
+	default:
+		return ErrInvalidPolicy
+	}
+	return nil
+}
+
+func (policy Policy) capacity() int {
+	if policy.Burst > 0 {
+		return policy.Burst
+	}
+	return policy.Limit
+}
@@ -0,0 +1,43 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	// ClaimName is the token claim which carries the tenant identifier
+	ClaimName = "tenantId"
+	// HeaderName is used to propagate the tenant identifier on messages
+	HeaderName = "X-Tenant-Id"
+)
+
+var ErrTenantNotFound = errors.New("tenant not found in context")
+
+type contextKey struct{}
+
+// NewContext returns a copy of the parent context carrying the tenant identifier
+func NewContext(parent context.Context, tenantId string) context.Context {
+	return context.WithValue(parent, contextKey{}, tenantId)
+}
+
+// FromContext returns the tenant identifier stored in the context, if any
+func FromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	tenantId, ok := ctx.Value(contextKey{}).(string)
+	if !ok || tenantId == "" {
+		return "", false
+	}
+	return tenantId, true
+}
+
+// Require returns the tenant identifier stored in the context or ErrTenantNotFound
+func Require(ctx context.Context) (string, error) {
+	tenantId, ok := FromContext(ctx)
+	if !ok {
+		return "", ErrTenantNotFound
+	}
+	return tenantId, nil
+}
@@ -0,0 +1,76 @@
+package tracing
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// IExporter receives the finished spans. Implementations must be safe for concurrent use
+type IExporter interface {
+	Export(span SpanData)
+}
+
+// NoopExporter discards the spans
+type NoopExporter struct{}
+
+func (NoopExporter) Export(span SpanData) {}
+
+// InMemoryExporter keeps the spans in memory. Meant for tests
+type InMemoryExporter struct {
+	mutex sync.Mutex
+	spans []SpanData
+}
+
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+func (exporter *InMemoryExporter) Export(span SpanData) {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+	exporter.spans = append(exporter.spans, span)
+}
+
+// Spans returns the exported spans in the order they ended
+func (exporter *InMemoryExporter) Spans() []SpanData {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+	spans := make([]SpanData, len(exporter.spans))
+	copy(spans, exporter.spans)
+	return spans
+}
+
+func (exporter *InMemoryExporter) Reset() {
+	exporter.mutex.Lock()
+	defer exporter.mutex.Unlock()
+	exporter.spans = nil
+}
+
+// LogExporter writes the spans as log entries, so they can be shipped by the log collector
+type LogExporter struct {
+	Logger *logrus.Entry
+}
+
+func NewLogExporter(logger *logrus.Entry) *LogExporter {
+	return &LogExporter{Logger: logger}
+}
+
+func (exporter *LogExporter) Export(span SpanData) {
+	fields := logrus.Fields{
+		"trace_id":    span.SpanContext.TraceID.String(),
+		"span_id":     span.SpanContext.SpanID.String(),
+		"span_kind":   span.Kind,
+		"start_time":  span.StartTime,
+		"duration_ms": float64(span.EndTime.Sub(span.StartTime).Microseconds()) / 1000,
+		"status":      span.Status,
+		"attributes":  span.Attributes,
+	}
+	if span.ParentSpanID.IsValid() {
+		fields["parent_span_id"] = span.ParentSpanID.String()
+	}
+	if span.StatusMessage != "" {
+		fields["status_message"] = span.StatusMessage
+	}
+	exporter.Logger.WithFields(fields).Info(span.Name)
+}
@@ -0,0 +1,78 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// W3C Trace Context header (https://www.w3.org/TR/trace-context/)
+const TraceparentHeader = "traceparent"
+
+var ErrInvalidTraceparent = errors.New("invalid traceparent")
+
+// Traceparent formats the span context as a traceparent header value (version 00)
+func (spanContext SpanContext) Traceparent() string {
+	flags := "00"
+	if spanContext.Sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", spanContext.TraceID, spanContext.SpanID, flags)
+}
+
+// ParseTraceparent parses a traceparent header value
+func ParseTraceparent(value string) (SpanContext, error) {
+	spanContext := SpanContext{}
+
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return spanContext, ErrInvalidTraceparent
+	}
+
+	if len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 || strings.ToLower(value) != value {
+		return spanContext, ErrInvalidTraceparent
+	}
+
+	traceId, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return spanContext, ErrInvalidTraceparent
+	}
+	spanId, err := hex.DecodeString(parts[2])
+	if err != nil {
+		return spanContext, ErrInvalidTraceparent
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return spanContext, ErrInvalidTraceparent
+	}
+
+	copy(spanContext.TraceID[:], traceId)
+	copy(spanContext.SpanID[:], spanId)
+	spanContext.Sampled = flags[0]&0x01 == 0x01
+	spanContext.Remote = true
+
+	if !spanContext.IsValid() {
+		return SpanContext{}, ErrInvalidTraceparent
+	}
+	return spanContext, nil
+}
+
+// Inject writes the span context carried by the context on the headers
+func Inject(ctx context.Context, header http.Header) {
+	spanContext := SpanContextFromContext(ctx)
+	if spanContext.IsValid() {
+		header.Set(TraceparentHeader, spanContext.Traceparent())
+	}
+}
+
+// Extract returns a copy of the parent context carrying the span context sent on the headers (if valid)
+func Extract(parent context.Context, header http.Header) context.Context {
+	spanContext, err := ParseTraceparent(header.Get(TraceparentHeader))
+	if err != nil {
+		return parent
+	}
+	return ContextWithRemoteSpanContext(parent, spanContext)
+}
@@ -0,0 +1,149 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+type TraceID [16]byte
+type SpanID [8]byte
+
+type SpanKind string
+
+const (
+	SpanKindInternal SpanKind = "internal"
+	SpanKindServer   SpanKind = "server"
+	SpanKindClient   SpanKind = "client"
+	SpanKindProducer SpanKind = "producer"
+	SpanKindConsumer SpanKind = "consumer"
+)
+
+type StatusCode string
+
+const (
+	StatusUnset StatusCode = "unset"
+	StatusOk    StatusCode = "ok"
+	StatusError StatusCode = "error"
+)
+
+// SpanContext is the part of a span propagated across process boundaries
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+	Remote  bool // created from propagated headers
+}
+
+// SpanData is the snapshot of a finished span sent to the exporter
+type SpanData struct {
+	Name          string
+	Kind          SpanKind
+	SpanContext   SpanContext
+	ParentSpanID  SpanID
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    map[string]interface{}
+	Status        StatusCode
+	StatusMessage string
+}
+
+type Span struct {
+	mutex    sync.Mutex
+	data     SpanData
+	ended    bool
+	exporter IExporter
+}
+
+func (traceId TraceID) String() string {
+	return hex.EncodeToString(traceId[:])
+}
+
+func (traceId TraceID) IsValid() bool {
+	return traceId != TraceID{}
+}
+
+func (spanId SpanID) String() string {
+	return hex.EncodeToString(spanId[:])
+}
+
+func (spanId SpanID) IsValid() bool {
+	return spanId != SpanID{}
+}
+
+func (spanContext SpanContext) IsValid() bool {
+	return spanContext.TraceID.IsValid() && spanContext.SpanID.IsValid()
+}
+
+// SpanContext returns the identifiers of the span
+func (span *Span) SpanContext() SpanContext {
+	if span == nil {
+		return SpanContext{}
+	}
+	return span.data.SpanContext
+}
+
+// SetAttribute records an attribute using the OpenTelemetry semantic conventions names (e.g. http.method)
+func (span *Span) SetAttribute(key string, value interface{}) {
+	if span == nil {
+		return
+	}
+	span.mutex.Lock()
+	defer span.mutex.Unlock()
+	span.data.Attributes[key] = value
+}
+
+func (span *Span) SetStatus(code StatusCode, message string) {
+	if span == nil {
+		return
+	}
+	span.mutex.Lock()
+	defer span.mutex.Unlock()
+	span.data.Status = code
+	span.data.StatusMessage = message
+}
+
+// RecordError sets the error status on the span
+func (span *Span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	span.SetStatus(StatusError, err.Error())
+}
+
+// End finishes the span and sends it to the exporter. Calls after the first one are ignored
+func (span *Span) End() {
+	if span == nil {
+		return
+	}
+	span.mutex.Lock()
+	if span.ended {
+		span.mutex.Unlock()
+		return
+	}
+	span.ended = true
+	span.data.EndTime = time.Now()
+	data := span.data
+	data.Attributes = make(map[string]interface{}, len(span.data.Attributes))
+	for key, value := range span.data.Attributes {
+		data.Attributes[key] = value
+	}
+	span.mutex.Unlock()
+
+	if span.exporter != nil && data.SpanContext.Sampled {
+		span.exporter.Export(data)
+	}
+}
+
+func newTraceID() TraceID {
+	var traceId TraceID
+	rand.Read(traceId[:])
+	return traceId
+}
+
+func newSpanID() SpanID {
+	var spanId SpanID
+	rand.Read(spanId[:])
+	return spanId
+}
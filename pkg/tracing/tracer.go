@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var (
+	exporter      IExporter = NoopExporter{}
+	exporterMutex sync.RWMutex
+)
+
+type spanContextKey struct{}
+type remoteContextKey struct{}
+
+// SetExporter replaces the exporter which receives the finished spans (discarded by default)
+func SetExporter(newExporter IExporter) {
+	exporterMutex.Lock()
+	defer exporterMutex.Unlock()
+	exporter = newExporter
+}
+
+func getExporter() IExporter {
+	exporterMutex.RLock()
+	defer exporterMutex.RUnlock()
+	return exporter
+}
+
+// StartSpan starts a span which is a child of the span (local or remote) carried by the context, or a new trace root.
+// The returned context carries the new span and must be used by the operations it encloses
+func StartSpan(ctx context.Context, name string, kind SpanKind) (context.Context, *Span) {
+	parent := SpanContextFromContext(ctx)
+
+	spanContext := SpanContext{
+		TraceID: parent.TraceID,
+		SpanID:  newSpanID(),
+		Sampled: true,
+	}
+	if parent.IsValid() {
+		spanContext.Sampled = parent.Sampled
+	} else {
+		spanContext.TraceID = newTraceID()
+	}
+
+	span := &Span{
+		data: SpanData{
+			Name:         name,
+			Kind:         kind,
+			SpanContext:  spanContext,
+			ParentSpanID: parent.SpanID,
+			StartTime:    time.Now(),
+			Attributes:   make(map[string]interface{}),
+			Status:       StatusUnset,
+		},
+		exporter: getExporter(),
+	}
+
+	return context.WithValue(ctx, spanContextKey{}, span), span
+}
+
+// SpanFromContext returns the current local span, or nil
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+// SpanContextFromContext returns the span context of the current local span, or of the remote parent extracted from
+// the propagation headers
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.SpanContext()
+	}
+	if ctx == nil {
+		return SpanContext{}
+	}
+	spanContext, _ := ctx.Value(remoteContextKey{}).(SpanContext)
+	return spanContext
+}
+
+// ContextWithRemoteSpanContext returns a copy of the parent context carrying the span context received from another process
+func ContextWithRemoteSpanContext(parent context.Context, spanContext SpanContext) context.Context {
+	spanContext.Remote = true
+	return context.WithValue(parent, remoteContextKey{}, spanContext)
+}
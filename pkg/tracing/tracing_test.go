@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	spanContext, err := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.Nil(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spanContext.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", spanContext.SpanID.String())
+	assert.True(t, spanContext.Sampled)
+	assert.True(t, spanContext.Remote)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", spanContext.Traceparent())
+
+	invalidValues := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	}
+	for _, value := range invalidValues {
+		_, err := ParseTraceparent(value)
+		assert.Equal(t, ErrInvalidTraceparent, err, value)
+	}
+}
+
+func TestStartSpan(t *testing.T) {
+	exporter := NewInMemoryExporter()
+	SetExporter(exporter)
+	defer SetExporter(NoopExporter{})
+
+	header := http.Header{}
+	header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	ctx := Extract(context.Background(), header)
+	ctx, server := StartSpan(ctx, "GET /periods", SpanKindServer)
+	clientCtx, client := StartSpan(ctx, "HTTP GET", SpanKindClient)
+
+	outgoing := http.Header{}
+	Inject(clientCtx, outgoing)
+	assert.Equal(t, client.SpanContext().Traceparent(), outgoing.Get(TraceparentHeader))
+
+	client.RecordError(errors.New("connection refused"))
+	client.End()
+	server.SetAttribute("http.status_code", 200)
+	server.End()
+	server.End()
+
+	spans := exporter.Spans()
+	assert.Len(t, spans, 2)
+
+	assert.Equal(t, "HTTP GET", spans[0].Name)
+	assert.Equal(t, StatusError, spans[0].Status)
+	assert.Equal(t, server.SpanContext().SpanID, spans[0].ParentSpanID)
+
+	assert.Equal(t, "GET /periods", spans[1].Name)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[1].SpanContext.TraceID.String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[1].ParentSpanID.String())
+	assert.Equal(t, 200, spans[1].Attributes["http.status_code"])
+}
+
+func TestUnsampledSpansAreNotExported(t *testing.T) {
+	exporter := NewInMemoryExporter()
+	SetExporter(exporter)
+	defer SetExporter(NoopExporter{})
+
+	header := http.Header{}
+	header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+
+	_, span := StartSpan(Extract(context.Background(), header), "GET /periods", SpanKindServer)
+	span.End()
+
+	assert.Empty(t, exporter.Spans())
+}
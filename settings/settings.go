@@ -21,18 +21,57 @@ type ConfigType struct {
 	DatabaseConnectionServerPort    int    `env:"DATABASE_SERVER_PORT" default:"3306"`
 	DatabaseName                    string `env:"DATABASE_NAME"`
 
+	// Connection, read and write timeouts (seconds) of the driver, for the queries without a context deadline. Zero
+	// keeps the driver defaults (no timeouts)
+	DatabaseTimeout int `env:"DATABASE_TIMEOUT" default:"0"`
+
 	// HTTP Port to expose the API
 	ApiHttpPort int `env:"API_PORT"`
 
+	// Proxies (CIDRs, e.g. 10.0.0.0/8) trusted to set the client IP on the X-Forwarded-For header. The IP of the
+	// connection is used when empty
+	TrustedProxies []string `env:"TRUSTED_PROXIES"`
+
+	// Default time (seconds) to answer a request. Routes can set their own
+	RequestTimeout int `env:"REQUEST_TIMEOUT" default:"30"`
+
+	// CORS. Any origin is allowed on development when no origin is configured; the origins are required elsewhere
+	CORSAllowOrigins     []string `env:"CORS_ALLOW_ORIGINS"`
+	CORSAllowMethods     []string `env:"CORS_ALLOW_METHODS" default:"GET,HEAD,POST,PUT,PATCH,DELETE"`
+	CORSAllowHeaders     []string `env:"CORS_ALLOW_HEADERS" default:"Authorization,Content-Type,X-API-Key,X-CSRF-Token,X-Request-ID,X-Correlation-ID,Idempotency-Key"`
+	CORSExposeHeaders    []string `env:"CORS_EXPOSE_HEADERS" default:"X-Request-ID,X-Correlation-ID,Retry-After,Link"`
+	CORSAllowCredentials bool     `env:"CORS_ALLOW_CREDENTIALS" default:"false"`
+	CORSMaxAge           int      `env:"CORS_MAX_AGE" default:"600"`
+
+	// Security headers. Empty values use the defaults of the environment (HSTS is not sent on development)
+	HSTSMaxAge            int    `env:"HSTS_MAX_AGE" default:"31536000"`
+	ContentSecurityPolicy string `env:"CONTENT_SECURITY_POLICY"`
+	FrameOptions          string `env:"FRAME_OPTIONS"`
+	ReferrerPolicy        string `env:"REFERRER_POLICY"`
+
+	// Page size of the list endpoints (limit query parameter)
+	PageDefaultLimit int `env:"PAGE_DEFAULT_LIMIT" default:"20"`
+	PageMaxLimit     int `env:"PAGE_MAX_LIMIT" default:"100"`
+
 	// Key used to sign the token
 	SecretKey []byte `env:"SECRET_KEY"`
 
+	// Client credentials token expiration (minutes)
+	ClientTokenExpiration int `env:"CLIENT_TOKEN_EXPIRATION" default:"60"`
+
+	// Session cookies (web front-end)
+	SessionCookieDomain string `env:"SESSION_COOKIE_DOMAIN"`
+	SessionCookieSecure bool   `env:"SESSION_COOKIE_SECURE" default:"true"`
+
 	ServerCloseWait int `env:"SERVER_CLOSEWAIT" default:"10"`
 
 	// Log files
 	LogAccessFile string `env:"LOG_ACCESS_FILE" default:"./access.log"`
 	LogAppFile    string `env:"LOG_APP_FILE" default:"./app.log"`
 	LogLevel      string `env:"LOG_LEVEL" default:"INFO"`
+
+	// Adds the request and response bodies (redacted) to the access log
+	LogCaptureBodies bool `env:"LOG_CAPTURE_BODIES" default:"false"`
 }
 
 var Config ConfigType